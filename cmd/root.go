@@ -2,8 +2,13 @@ package cmd
 
 import (
 	"bytes"
+	"fmt"
+	htmltemplate "html/template"
 	"os"
 	"os/signal"
+	"path"
+	"path/filepath"
+	"strings"
 	"text/template"
 	"time"
 
@@ -24,47 +29,238 @@ var rootCmd = &cobra.Command{
 		startedAt := time.Now()
 		pathFlag, _ := cmd.Flags().GetString("path")
 		outputFlag, _ := cmd.Flags().GetString("output")
+		extractorFlag, _ := cmd.Flags().GetString("extractor")
+		unexportedFlag, _ := cmd.Flags().GetBool("unexported")
+		goosFlag, _ := cmd.Flags().GetString("goos")
+		goarchFlag, _ := cmd.Flags().GetString("goarch")
+		tagsFlag, _ := cmd.Flags().GetStringSlice("tags")
+		platformsFlag, _ := cmd.Flags().GetStringSlice("platforms")
+		linkURLFlag, _ := cmd.Flags().GetString("link-url")
+		moduleLinkURLFlag, _ := cmd.Flags().GetString("module-link-url")
+		notesFlag, _ := cmd.Flags().GetStringSlice("notes")
+		sourceURLFlag, _ := cmd.Flags().GetString("source-url")
+		templateFlag, _ := cmd.Flags().GetString("template")
+		templateNameFlag, _ := cmd.Flags().GetString("template-name")
+		partialsFlag, _ := cmd.Flags().GetString("partials")
+		tocFlag, _ := cmd.Flags().GetBool("toc")
+		headingLevelFlag, _ := cmd.Flags().GetInt("heading-level")
+		formatFlag, _ := cmd.Flags().GetString("format")
 
-		godoc, err := internal.GetGoDoc(pathFlag)
-		if err != nil {
-			return err
+		opts := internal.LoadOptions{
+			Unexported: unexportedFlag,
+			GOOS:       goosFlag,
+			GOARCH:     goarchFlag,
+			Tags:       tagsFlag,
+		}
+
+		if templateFlag != "" && cmd.Flags().Changed("template-name") {
+			return fmt.Errorf("--template and --template-name cannot be used together")
+		}
+		if formatFlag == "html" && cmd.Flags().Changed("heading-level") {
+			return fmt.Errorf("--heading-level can only be used with --format markdown")
+		}
+		var t *template.Template
+		var htmlTemplate *htmltemplate.Template
+		var err error
+		switch formatFlag {
+		case "markdown":
+			t, err = internal.ParseTemplate(templateFlag, templateNameFlag)
+			if err == nil && partialsFlag != "" {
+				err = internal.ParsePartials(t, partialsFlag)
+			}
+		case "html":
+			htmlTemplate, err = internal.ParseHTMLTemplate(templateFlag, templateNameFlag)
+			if err == nil && partialsFlag != "" {
+				err = internal.ParseHTMLPartials(htmlTemplate, partialsFlag)
+			}
+			if !cmd.Flags().Changed("module-link-url") {
+				moduleLinkURLFlag = internal.DefaultHTMLModuleLinkURL
+			}
+		default:
+			err = fmt.Errorf("unknown format %q, must be one of: markdown, html", formatFlag)
 		}
-		err = godoc.Parse()
 		if err != nil {
 			return err
 		}
+		if level, ok := internal.HeadingLevels[templateNameFlag]; ok && templateFlag == "" && !cmd.Flags().Changed("heading-level") {
+			headingLevelFlag = level
+		}
+		if headingLevelFlag < 1 || headingLevelFlag > 6 {
+			return fmt.Errorf("invalid heading level %d, must be between 1 and 6", headingLevelFlag)
+		}
 
-		t := template.New("godoc").Funcs(sprig.TxtFuncMap())
-		t, err = t.Parse(internal.DefaultMarkdownTemplate)
-		if err != nil {
-			return err
+		// generate loads the package at path and executes the template with it.
+		generate := func(path string) (internal.Package, []byte, error) {
+			load := func(opts internal.LoadOptions) (internal.Package, error) {
+				switch extractorFlag {
+				case "ast":
+					return internal.GetPackage(path, opts)
+				case "godoc":
+					godoc, err := internal.GetGoDoc(path, opts)
+					if err != nil {
+						return internal.Package{}, err
+					}
+					err = godoc.Parse()
+					return godoc.Package, err
+				default:
+					return internal.Package{}, fmt.Errorf("unknown extractor %q, must be one of: ast, godoc", extractorFlag)
+				}
+			}
+
+			var pkg internal.Package
+			var err error
+			if len(platformsFlag) > 0 {
+				pkg, err = internal.LoadPlatforms(platformsFlag, opts, load)
+			} else {
+				pkg, err = load(opts)
+			}
+			if err != nil {
+				return pkg, nil, err
+			}
+			pkg.FilterNotes(notesFlag)
+			// The HTML template lists the table of contents in its sidebar.
+			if tocFlag || formatFlag == "html" {
+				pkg.SetTOC()
+			}
+
+			repo, err := internal.DetectSourceRepo(pkg.Dir)
+			if err != nil {
+				pterm.Debug.Printfln("could not detect the git repository of the package: %s", err)
+			}
+			pkg.SetModuleVersion(repo)
+			err = pkg.LinkSources(sourceURLFlag, repo)
+			if err != nil {
+				return pkg, nil, err
+			}
+
+			links, err := internal.NewDocLinks(&pkg, moduleLinkURLFlag, linkURLFlag)
+			if err != nil {
+				return pkg, nil, err
+			}
+			var tpl bytes.Buffer
+			if htmlTemplate != nil {
+				pkg.FormatDocs(links.HTMLDoc, headingLevelFlag)
+				htmlTemplate.Funcs(internal.HTMLFuncMap(links))
+				err = htmlTemplate.Execute(&tpl, pkg)
+			} else {
+				pkg.FormatDocs(links.MarkdownDoc, headingLevelFlag)
+				t.Funcs(internal.FuncMap(links, headingLevelFlag))
+				// err = t.Execute(&tpl, internal.Package{})
+				// err = t.Execute(&tpl, internal.GenerateTestPackage())
+				err = t.Execute(&tpl, pkg)
+			}
+			for _, warning := range links.Warnings {
+				pterm.Warning.Printfln("%s: %s", pkg.Name, warning)
+			}
+			if err != nil {
+				return pkg, nil, fmt.Errorf("error while executing template: %w", err)
+			}
+			return pkg, tpl.Bytes(), nil
 		}
 
-		var tpl bytes.Buffer
-		// err = t.Execute(&tpl, internal.Package{})
-		// err = t.Execute(&tpl, internal.GenerateTestPackage())
-		err = t.Execute(&tpl, godoc.Package)
+		if strings.HasSuffix(pathFlag, "...") {
+			return generatePackages(pathFlag, outputFlag, formatFlag, opts, generate, startedAt)
+		}
+
+		pkg, docs, err := generate(pathFlag)
 		if err != nil {
 			return err
 		}
 
 		if outputFlag != "" {
-			err := os.WriteFile(outputFlag, tpl.Bytes(), 0600)
+			err := os.WriteFile(outputFlag, docs, 0600)
 			if err != nil {
 				return err
 			}
 		} else {
-			pterm.Printfln("%s", tpl.String())
+			pterm.Printfln("%s", docs)
 		}
 
 		if !pterm.RawOutput {
-			pterm.Success.Printfln("Successfully generated docs for %s! %s", pterm.Magenta(godoc.Package.Name), pterm.Gray("("+time.Since(startedAt).String()+")"))
+			pterm.Success.Printfln("Successfully generated docs for %s! %s", pterm.Magenta(pkg.Name), pterm.Gray("("+time.Since(startedAt).String()+")"))
 		}
 
 		return nil
 	},
 }
 
+// generatePackages generates the docs of all packages matching pattern into the output directory.
+// The directories of the docs mirror the package tree and an index lists all packages.
+// Markdown docs are written to README.md files and listed in index.md, HTML docs are written to index.html files and
+// listed in packages.html.
+func generatePackages(pattern, output, format string, opts internal.LoadOptions, generate func(path string) (internal.Package, []byte, error), startedAt time.Time) error {
+	if output == "" {
+		return fmt.Errorf("--output has to be set to a directory, when generating docs for multiple packages")
+	}
+
+	dirs, err := internal.ListPackages(pattern, opts)
+	if err != nil {
+		return err
+	}
+
+	docFile, indexFile := "README.md", "index.md"
+	if format == "html" {
+		docFile, indexFile = "index.html", "packages.html"
+	}
+
+	var index internal.Index
+	for _, dir := range dirs {
+		pkg, docs, err := generate(dir.Dir)
+		if err != nil {
+			return fmt.Errorf("error while generating docs for %q: %w", dir.ImportPath, err)
+		}
+
+		file := filepath.Join(output, filepath.FromSlash(dir.Path), docFile)
+		err = os.MkdirAll(filepath.Dir(file), 0755)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(file, docs, 0600)
+		if err != nil {
+			return err
+		}
+		pterm.Debug.Printfln("generated %s", file)
+
+		index.Packages = append(index.Packages, internal.IndexEntry{
+			Name:       pkg.Name,
+			ImportPath: dir.ImportPath,
+			Synopsis:   pkg.Synopsis,
+			Link:       path.Join(dir.Path, docFile),
+		})
+	}
+
+	var tpl bytes.Buffer
+	if format == "html" {
+		t, err := htmltemplate.New("index").Funcs(sprig.HtmlFuncMap()).Funcs(internal.HTMLFuncMap(nil)).Parse(internal.DefaultHTMLIndexTemplate)
+		if err != nil {
+			return err
+		}
+		err = t.Execute(&tpl, index)
+		if err != nil {
+			return err
+		}
+	} else {
+		t, err := template.New("index").Funcs(sprig.TxtFuncMap()).Funcs(internal.FuncMap(nil, 1)).Parse(internal.DefaultIndexTemplate)
+		if err != nil {
+			return err
+		}
+		err = t.Execute(&tpl, index)
+		if err != nil {
+			return err
+		}
+	}
+	err = os.WriteFile(filepath.Join(output, indexFile), tpl.Bytes(), 0600)
+	if err != nil {
+		return err
+	}
+
+	if !pterm.RawOutput {
+		pterm.Success.Printfln("Successfully generated docs for %d packages! %s", len(dirs), pterm.Gray("("+time.Since(startedAt).String()+")"))
+	}
+
+	return nil
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -94,8 +290,24 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&pterm.RawOutput, "raw", "", false, "print unstyled raw output (set it if output is written to a file)")
 	rootCmd.PersistentFlags().BoolVarP(&pcli.DisableUpdateChecking, "disable-update-checks", "", false, "disables update checks")
 
-	rootCmd.Flags().StringP("path", "p", ".", "path to search for go files")
-	rootCmd.Flags().StringP("output", "o", "", "output path")
+	rootCmd.Flags().StringP("path", "p", ".", "path to search for go files, use a pattern like ./... to generate docs for multiple packages")
+	rootCmd.Flags().StringP("output", "o", "", "output path, or the output directory when generating docs for multiple packages")
+	rootCmd.Flags().StringP("extractor", "e", "ast", "how docs are extracted: ast (parse the source files) or godoc (parse the output of \"go doc\")")
+	rootCmd.Flags().String("goos", "", "GOOS of the build context (default: the host's GOOS)")
+	rootCmd.Flags().String("goarch", "", "GOARCH of the build context (default: the host's GOARCH)")
+	rootCmd.Flags().StringSlice("tags", nil, "build tags of the build context")
+	rootCmd.Flags().StringSlice("platforms", nil, "platforms (goos[/goarch][:tag+tag...]) to load the package for, to show on which platforms each symbol is available")
+	rootCmd.Flags().Bool("unexported", false, "include unexported declarations and struct fields")
+	rootCmd.Flags().String("source-url", "", "URL template for source links, like https://git.example.com/{{.Repo}}/blob/{{.Ref}}/{{.File}}#L{{.Line}} (default: detected from the git remote)")
+	rootCmd.Flags().StringSlice("notes", []string{"BUG", "TODO"}, "markers of the notes, like BUG(who): ..., which are listed in the docs")
+	rootCmd.Flags().StringP("format", "f", "markdown", "output format: markdown or html")
+	rootCmd.Flags().StringP("template", "t", "", "path to a custom template file")
+	rootCmd.Flags().String("template-name", "default", "name of the built-in template: "+strings.Join(internal.TemplateNames(), ", "))
+	rootCmd.Flags().String("partials", "", "directory of template files, whose {{define \"name\"}} blocks override the blocks of the template, like \"structs\"")
+	rootCmd.Flags().Int("heading-level", 1, "level of the top markdown heading, the headings of the template and of doc comments are shifted by it (the readme-section template starts at 2)")
+	rootCmd.Flags().Bool("toc", false, "add a table of contents, which links the signatures of all symbols")
+	rootCmd.Flags().String("link-url", internal.DefaultExternalLinkURL, "URL template for doc links to packages outside of the module")
+	rootCmd.Flags().String("module-link-url", internal.DefaultModuleLinkURL, "URL template for doc links to other packages of the module (html links to index.html by default)")
 
 	// Use https://github.com/pterm/pcli to style the output of cobra.
 	pcli.SetRepo("MarvinJWendt/gomark")
@@ -0,0 +1,748 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/constant"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// LoadOptions configures, which parts of a package are documented.
+type LoadOptions struct {
+	// Unexported includes unexported declarations and struct fields.
+	Unexported bool
+
+	// GOOS, GOARCH and Tags select the build context, which decides the files of the package.
+	// Empty values default to the build context of the host.
+	GOOS   string
+	GOARCH string
+	Tags   []string
+}
+
+// env returns the environment for the go command, which selects the build context of the options.
+func (o LoadOptions) env() []string {
+	env := os.Environ()
+	if o.GOOS != "" {
+		env = append(env, "GOOS="+o.GOOS)
+	}
+	if o.GOARCH != "" {
+		env = append(env, "GOARCH="+o.GOARCH)
+	}
+
+	return env
+}
+
+// buildContext returns the go/build context of the options.
+func (o LoadOptions) buildContext() build.Context {
+	ctx := build.Default
+	if o.GOOS != "" {
+		ctx.GOOS = o.GOOS
+	}
+	if o.GOARCH != "" {
+		ctx.GOARCH = o.GOARCH
+	}
+	ctx.BuildTags = append(ctx.BuildTags, o.Tags...)
+
+	return ctx
+}
+
+// GetPackage loads a package with go/packages, including its type information, and returns its documentation.
+// pkgPath can either be a directory or an import path.
+func GetPackage(pkgPath string, opts LoadOptions) (Package, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedModule,
+		Env:  opts.env(),
+	}
+	if len(opts.Tags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(opts.Tags, ",")}
+	}
+	pattern := pkgPath
+	if info, err := os.Stat(pkgPath); err == nil && info.IsDir() {
+		cfg.Dir = pkgPath
+		pattern = "."
+	}
+
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return Package{}, fmt.Errorf("error while loading package %q: %w", pkgPath, err)
+	}
+	if len(pkgs) != 1 {
+		return Package{}, fmt.Errorf("expected exactly one package at %q, found %d", pkgPath, len(pkgs))
+	}
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return Package{}, fmt.Errorf("error while loading package %q: %w", pkgPath, pkg.Errors[0])
+	}
+
+	testFiles, err := parseTestFiles(pkg, opts.buildContext())
+	if err != nil {
+		return Package{}, err
+	}
+
+	// The parser and the command have to be created before go/doc filters the syntax trees.
+	p := newAstParser(pkg)
+	var command *Command
+	if pkg.Name == "main" {
+		pkgs, err := loadCommandPackages(cfg, pkg)
+		if err != nil {
+			return Package{}, err
+		}
+		command = parseCommand(path.Base(pkg.PkgPath), pkgs)
+	}
+
+	var mode doc.Mode
+	if opts.Unexported {
+		mode = doc.AllDecls
+	}
+	docPkg, err := doc.NewFromFiles(pkg.Fset, append(append([]*ast.File{}, pkg.Syntax...), testFiles...), pkg.PkgPath, mode)
+	if err != nil {
+		return Package{}, fmt.Errorf("error while reading docs of %q: %w", pkgPath, err)
+	}
+
+	result := p.parsePackage(docPkg)
+	result.ImportPath = pkg.PkgPath
+	if pkg.Module != nil {
+		result.ModulePath = pkg.Module.Path
+		result.ModuleVersion = pkg.Module.Version
+		result.GoVersion = pkg.Module.GoVersion
+		result.moduleDir = pkg.Module.Dir
+	}
+	result.imports = packageImports(pkg)
+	result.Command = command
+	if len(pkg.GoFiles) > 0 {
+		result.Dir = filepath.Dir(pkg.GoFiles[0])
+	}
+	result.markExported()
+
+	return result, nil
+}
+
+// packageImports maps the names, under which packages are imported in the files of pkg, to their import paths.
+func packageImports(pkg *packages.Package) map[string]string {
+	imports := make(map[string]string)
+	for _, file := range pkg.Syntax {
+		for _, spec := range file.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			switch {
+			case spec.Name != nil && spec.Name.Name != "_" && spec.Name.Name != ".":
+				imports[spec.Name.Name] = path
+			case spec.Name == nil && pkg.Imports[path] != nil:
+				imports[pkg.Imports[path].Name] = path
+			}
+		}
+	}
+
+	return imports
+}
+
+// parseTestFiles parses the _test.go files of a package, which go/doc uses to extract examples.
+func parseTestFiles(pkg *packages.Package, ctx build.Context) ([]*ast.File, error) {
+	if len(pkg.GoFiles) == 0 {
+		return nil, nil
+	}
+
+	dir := filepath.Dir(pkg.GoFiles[0])
+	bp, err := ctx.ImportDir(dir, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error while searching test files of %q: %w", pkg.PkgPath, err)
+	}
+
+	var files []*ast.File
+	for _, name := range append(bp.TestGoFiles, bp.XTestGoFiles...) {
+		file, err := parser.ParseFile(pkg.Fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing %s: %w", name, err)
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+type astParser struct {
+	fset         *token.FileSet
+	files        []*ast.File
+	info         *types.Info
+	stringValues map[string]map[int64]string
+}
+
+func newAstParser(pkg *packages.Package) *astParser {
+	return &astParser{fset: pkg.Fset, files: pkg.Syntax, info: pkg.TypesInfo, stringValues: stringValues(pkg)}
+}
+
+func (p *astParser) parsePackage(docPkg *doc.Package) (pkg Package) {
+	pkg.Name = docPkg.Name
+	pkg.Doc = strings.TrimSpace(docPkg.Doc)
+	pkg.Synopsis = docPkg.Synopsis(docPkg.Doc)
+	pkg.Examples = p.parseExamples(docPkg.Examples)
+	pkg.Notes = p.parseNotes(docPkg.Notes)
+
+	for _, c := range docPkg.Consts {
+		p.addValue(c, &pkg.Constants, &pkg.ConstantBlocks)
+	}
+	for _, v := range docPkg.Vars {
+		p.addValue(v, &pkg.Variables, &pkg.VariableBlocks)
+	}
+
+	for _, f := range docPkg.Funcs {
+		pkg.Functions = append(pkg.Functions, p.parseFunction(f))
+	}
+
+	for _, t := range docPkg.Types {
+		p.addType(&pkg, t)
+	}
+
+	return pkg
+}
+
+// addValue appends a const or var declaration either as a single value or, if it is grouped, as a block.
+// Blank identifiers, like in "var _ Interface = (*Type)(nil)", are skipped.
+func (p *astParser) addValue(value *doc.Value, singles *[]Variable, blocks *[]VariableBlock) {
+	decl := value.Decl
+	if !decl.Lparen.IsValid() && len(decl.Specs) == 1 && len(decl.Specs[0].(*ast.ValueSpec).Names) == 1 {
+		if decl.Specs[0].(*ast.ValueSpec).Names[0].Name == "_" {
+			return
+		}
+		v := p.parseValueSpec(decl.Specs[0].(*ast.ValueSpec), 0)
+		v.Doc = strings.TrimSpace(value.Doc)
+		v.Definition = p.print(&ast.GenDecl{Tok: decl.Tok, Specs: decl.Specs})
+		*singles = append(*singles, v)
+		return
+	}
+
+	block := VariableBlock{Doc: strings.TrimSpace(value.Doc)}
+	var definitions []string
+	for _, spec := range decl.Specs {
+		spec := spec.(*ast.ValueSpec)
+		definitions = append(definitions, p.print(&ast.ValueSpec{Names: spec.Names, Type: spec.Type, Values: spec.Values}))
+		for i, name := range spec.Names {
+			if name.Name == "_" {
+				continue
+			}
+			block.Variables = append(block.Variables, p.parseValueSpec(spec, i))
+		}
+	}
+	if len(block.Variables) == 0 {
+		return
+	}
+	block.Definition = strings.Join(definitions, "\n")
+	*blocks = append(*blocks, block)
+}
+
+func (p *astParser) parseValueSpec(spec *ast.ValueSpec, index int) (v Variable) {
+	v.Name = spec.Names[index].Name
+	v.Source = p.source(spec)
+	v.Doc = strings.TrimSpace(spec.Doc.Text())
+	if v.Doc == "" {
+		v.Doc = strings.TrimSpace(spec.Comment.Text())
+	}
+	// Specs with several names, like "X, Y = 1, 2", are split, unless the values come from a single call.
+	switch {
+	case len(spec.Names) == 1 || len(spec.Values) > 0 && len(spec.Values) != len(spec.Names):
+		v.Definition = p.print(&ast.ValueSpec{Names: spec.Names, Type: spec.Type, Values: spec.Values})
+	case len(spec.Values) == 0:
+		v.Definition = p.print(&ast.ValueSpec{Names: spec.Names[index : index+1], Type: spec.Type})
+	default:
+		v.Definition = p.print(&ast.ValueSpec{Names: spec.Names[index : index+1], Type: spec.Type, Values: spec.Values[index : index+1]})
+	}
+	if spec.Type != nil {
+		v.Type = p.print(spec.Type)
+	}
+	if index < len(spec.Values) {
+		v.Value = p.print(spec.Values[index])
+	}
+
+	if obj := p.info.Defs[spec.Names[index]]; obj != nil {
+		if v.Type == "" && !isUntyped(obj.Type()) {
+			v.Type = types.TypeString(obj.Type(), p.qualifier(obj.Pkg()))
+		}
+		v.TypeRefs = collectTypeRefs(obj.Type())
+
+		// Constants carry their evaluated value, which also covers implicit repetitions like iota.
+		if c, ok := obj.(*types.Const); ok {
+			v.Value = constantString(c.Val())
+		}
+	}
+
+	return
+}
+
+// constantString formats the value of a constant. Unlike constant.Value.String, strings are not shortened.
+func constantString(val constant.Value) string {
+	switch val.Kind() {
+	case constant.String:
+		return strconv.Quote(constant.StringVal(val))
+	case constant.Float, constant.Complex:
+		return val.String()
+	default:
+		return val.ExactString()
+	}
+}
+
+func (p *astParser) parseFunction(f *doc.Func) Function {
+	fn := newFunction(p.fset, f.Decl)
+	fn.Source = p.source(f.Decl)
+	fn.Doc = strings.TrimSpace(f.Doc)
+	fn.Examples = p.parseExamples(f.Examples)
+	if obj := p.info.Defs[f.Decl.Name]; obj != nil {
+		fn.TypeRefs = collectTypeRefs(obj.Type())
+	}
+
+	return fn
+}
+
+func (p *astParser) addType(pkg *Package, t *doc.Type) {
+	spec := t.Decl.Specs[0].(*ast.TypeSpec)
+	definition := p.printWithComments(&ast.GenDecl{Tok: token.TYPE, TokPos: t.Decl.TokPos, Specs: t.Decl.Specs})
+	docs := strings.TrimSpace(t.Doc)
+	examples := p.parseExamples(t.Examples)
+	source := p.source(t.Decl)
+
+	var methods []Function
+	for _, m := range t.Methods {
+		methods = append(methods, p.parseFunction(m))
+	}
+
+	enum, consts := p.parseEnum(t, spec)
+
+	var associated Associated
+	for _, c := range consts {
+		p.addValue(c, &associated.Constants, &associated.ConstantBlocks)
+	}
+	for _, v := range t.Vars {
+		p.addValue(v, &associated.Variables, &associated.VariableBlocks)
+	}
+	for _, f := range t.Funcs {
+		associated.Constructors = append(associated.Constructors, p.parseFunction(f))
+	}
+
+	typeParams := parseTypeParams(p.fset, spec.TypeParams)
+
+	switch typ := spec.Type.(type) {
+	case *ast.StructType:
+		pkg.Structs = append(pkg.Structs, Struct{
+			Doc:        docs,
+			Name:       t.Name,
+			Definition: definition,
+			Source:     source,
+			TypeParams: typeParams,
+			Associated: associated,
+			Fields:     p.parseFields(typ),
+			Functions:  methods,
+			Examples:   examples,
+		})
+	case *ast.InterfaceType:
+		i := Interface{
+			Doc:        docs,
+			Name:       t.Name,
+			Definition: definition,
+			Source:     source,
+			TypeParams: typeParams,
+			Associated: associated,
+			Examples:   examples,
+		}
+		for _, field := range typ.Methods.List {
+			if len(field.Names) == 0 {
+				if p.isTypeSetElement(field.Type) {
+					i.Unions = append(i.Unions, newUnion(p.fset, field.Type))
+					continue
+				}
+
+				embedded := Field{
+					Name:       embeddedName(field.Type),
+					Type:       p.print(field.Type),
+					Doc:        strings.TrimSpace(field.Doc.Text()),
+					Comment:    strings.TrimSpace(field.Comment.Text()),
+					IsEmbedded: true,
+				}
+				if typ := p.info.TypeOf(field.Type); typ != nil {
+					embedded.TypeRefs = collectTypeRefs(typ)
+				}
+				i.Embedded = append(i.Embedded, embedded)
+				continue
+			}
+
+			method := newMethod(p.fset, field.Names[0].Name, field.Type.(*ast.FuncType))
+			method.Source = p.source(field)
+			method.Doc = strings.TrimSpace(field.Doc.Text())
+			if method.Doc == "" {
+				method.Doc = strings.TrimSpace(field.Comment.Text())
+			}
+			if typ := p.info.TypeOf(field.Type); typ != nil {
+				method.TypeRefs = collectTypeRefs(typ)
+			}
+			i.Methods = append(i.Methods, method)
+		}
+
+		// Interfaces with type sets, or which embed comparable, can only be used as constraints.
+		if obj := p.info.Defs[spec.Name]; obj != nil {
+			if iface, ok := obj.Type().Underlying().(*types.Interface); ok {
+				i.IsConstraint = !iface.IsMethodSet()
+			}
+		} else {
+			i.IsConstraint = len(i.Unions) > 0
+		}
+		pkg.Interfaces = append(pkg.Interfaces, i)
+	default:
+		pkg.Types = append(pkg.Types, Type{
+			Doc:        docs,
+			Name:       t.Name,
+			Definition: definition,
+			Source:     source,
+			TypeParams: typeParams,
+			Enum:       enum,
+			Associated: associated,
+			Functions:  methods,
+			Examples:   examples,
+		})
+	}
+}
+
+// isTypeSetElement reports whether an embedded element of an interface is a type term or a union of type terms,
+// like ~int | ~string, instead of an embedded interface.
+func (p *astParser) isTypeSetElement(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.BinaryExpr:
+		return e.Op == token.OR
+	case *ast.UnaryExpr:
+		return e.Op == token.TILDE
+	}
+
+	if typ := p.info.TypeOf(expr); typ != nil {
+		_, isInterface := typ.Underlying().(*types.Interface)
+		return !isInterface
+	}
+
+	return false
+}
+
+// newUnion returns the terms of a type set element like ~int | ~string.
+func newUnion(fset *token.FileSet, expr ast.Expr) (u Union) {
+	var collect func(expr ast.Expr)
+	collect = func(expr ast.Expr) {
+		switch e := expr.(type) {
+		case *ast.BinaryExpr:
+			if e.Op == token.OR {
+				collect(e.X)
+				collect(e.Y)
+				return
+			}
+		case *ast.UnaryExpr:
+			if e.Op == token.TILDE {
+				u.Terms = append(u.Terms, Term{Type: printNode(fset, e.X), IsTilde: true})
+				return
+			}
+		case *ast.ParenExpr:
+			collect(e.X)
+			return
+		}
+		u.Terms = append(u.Terms, Term{Type: printNode(fset, expr)})
+	}
+	collect(expr)
+
+	return
+}
+
+var exampleOutputRx = regexp.MustCompile(`(?i)//[[:space:]]*(unordered )?output:`)
+
+func (p *astParser) parseExamples(examples []*doc.Example) (result []Example) {
+	for _, e := range examples {
+		code := p.print(&printer.CommentedNode{Node: e.Code, Comments: e.Comments})
+
+		// Function bodies are printed without their braces and indentation, and without the output comment.
+		if _, ok := e.Code.(*ast.BlockStmt); ok {
+			code = strings.TrimSuffix(strings.TrimPrefix(code, "{"), "}")
+			if loc := exampleOutputRx.FindStringIndex(code); loc != nil {
+				code = code[:loc[0]]
+			}
+			lines := strings.Split(strings.Trim(code, "\n"), "\n")
+			for i, line := range lines {
+				lines[i] = strings.TrimPrefix(line, "\t")
+			}
+			code = strings.TrimSpace(strings.Join(lines, "\n"))
+		}
+
+		result = append(result, Example{
+			Name:        e.Name,
+			Suffix:      e.Suffix,
+			Doc:         strings.TrimSpace(e.Doc),
+			Code:        code,
+			Output:      strings.TrimSpace(e.Output),
+			HasOutput:   e.Output != "" || e.EmptyOutput,
+			IsUnordered: e.Unordered,
+		})
+	}
+
+	return
+}
+
+func (p *astParser) parseNotes(notes map[string][]*doc.Note) map[string][]Note {
+	result := make(map[string][]Note)
+	for marker, list := range notes {
+		for _, note := range list {
+			pos := p.fset.Position(note.Pos)
+			result[marker] = append(result[marker], Note{
+				Author: note.UID,
+				Body:   strings.TrimSpace(note.Body),
+				File:   filepath.Base(pos.Filename),
+				Line:   pos.Line,
+			})
+		}
+	}
+
+	return result
+}
+
+func (p *astParser) parseFields(s *ast.StructType) (fields []Field) {
+	for _, field := range s.Fields.List {
+		f := Field{
+			Type:    p.print(field.Type),
+			Doc:     strings.TrimSpace(field.Doc.Text()),
+			Comment: strings.TrimSpace(field.Comment.Text()),
+			Source:  p.source(field),
+		}
+		if typ := p.info.TypeOf(field.Type); typ != nil {
+			f.TypeRefs = collectTypeRefs(typ)
+		}
+		if field.Tag != nil {
+			f.Tag, _ = strconv.Unquote(field.Tag.Value)
+			f.Tags = make(map[string]string)
+			for _, tag := range parseStructTag(f.Tag) {
+				f.Tags[tag.Key] = tag.Value
+			}
+		}
+
+		if len(field.Names) == 0 {
+			f.Name = embeddedName(field.Type)
+			f.IsEmbedded = true
+			fields = append(fields, f)
+			continue
+		}
+		for _, name := range field.Names {
+			f.Name = name.Name
+			fields = append(fields, f)
+		}
+	}
+
+	return
+}
+
+// embeddedName returns the field name of an embedded type, which is its unqualified type name.
+func embeddedName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedName(e.X)
+	case *ast.IndexListExpr:
+		return embeddedName(e.X)
+	case *ast.Ident:
+		return e.Name
+	}
+
+	return ""
+}
+
+// qualifier returns a types.Qualifier, which omits the package name for types of the given package.
+func (p *astParser) qualifier(pkg *types.Package) types.Qualifier {
+	return func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		return other.Name()
+	}
+}
+
+func isUntyped(t types.Type) bool {
+	basic, ok := t.(*types.Basic)
+	return ok && basic.Info()&types.IsUntyped != 0
+}
+
+// collectTypeRefs returns all named types, which are referenced by t. Predeclared types are left out.
+func collectTypeRefs(t types.Type) (refs []TypeRef) {
+	seen := make(map[TypeRef]bool)
+
+	var collect func(t types.Type)
+	collect = func(t types.Type) {
+		switch t := t.(type) {
+		case *types.Named:
+			if obj := t.Obj(); obj.Pkg() != nil {
+				ref := TypeRef{PkgPath: obj.Pkg().Path(), PkgName: obj.Pkg().Name(), Name: obj.Name()}
+				if !seen[ref] {
+					seen[ref] = true
+					refs = append(refs, ref)
+				}
+			}
+			for i := 0; i < t.TypeArgs().Len(); i++ {
+				collect(t.TypeArgs().At(i))
+			}
+		case *types.Alias:
+			if obj := t.Obj(); obj.Pkg() != nil {
+				ref := TypeRef{PkgPath: obj.Pkg().Path(), PkgName: obj.Pkg().Name(), Name: obj.Name()}
+				if !seen[ref] {
+					seen[ref] = true
+					refs = append(refs, ref)
+				}
+			}
+		case *types.Pointer:
+			collect(t.Elem())
+		case *types.Slice:
+			collect(t.Elem())
+		case *types.Array:
+			collect(t.Elem())
+		case *types.Chan:
+			collect(t.Elem())
+		case *types.Map:
+			collect(t.Key())
+			collect(t.Elem())
+		case *types.Tuple:
+			for i := 0; i < t.Len(); i++ {
+				collect(t.At(i).Type())
+			}
+		case *types.Signature:
+			if t.Recv() != nil {
+				collect(t.Recv().Type())
+			}
+			collect(t.Params())
+			collect(t.Results())
+		case *types.Struct:
+			for i := 0; i < t.NumFields(); i++ {
+				collect(t.Field(i).Type())
+			}
+		case *types.Interface:
+			for i := 0; i < t.NumExplicitMethods(); i++ {
+				collect(t.ExplicitMethod(i).Type())
+			}
+		}
+	}
+	collect(t)
+
+	return refs
+}
+
+// newFunction returns a Function with the definition and the parsed signature of a function declaration.
+func newFunction(fset *token.FileSet, decl *ast.FuncDecl) Function {
+	definition := *decl
+	definition.Doc = nil
+	definition.Body = nil
+
+	fn := Function{
+		Name:       decl.Name.Name,
+		Definition: printNode(fset, &definition),
+		TypeParams: parseTypeParams(fset, decl.Type.TypeParams),
+		Params:     parseParams(fset, decl.Type.Params),
+		Results:    parseParams(fset, decl.Type.Results),
+	}
+
+	if decl.Recv != nil && len(decl.Recv.List) == 1 {
+		recv := decl.Recv.List[0]
+		fn.Receiver = &Receiver{Type: printNode(fset, recv.Type)}
+		if len(recv.Names) > 0 {
+			fn.Receiver.Name = recv.Names[0].Name
+		}
+		if star, ok := recv.Type.(*ast.StarExpr); ok {
+			fn.Receiver.Type = printNode(fset, star.X)
+			fn.Receiver.IsPointer = true
+		}
+	}
+
+	return fn
+}
+
+// parseTypeParams returns one TypeParam for each name in a type parameter list.
+func parseTypeParams(fset *token.FileSet, list *ast.FieldList) (params []TypeParam) {
+	if list == nil {
+		return
+	}
+
+	for _, field := range list.List {
+		constraint := printNode(fset, field.Type)
+		for _, name := range field.Names {
+			params = append(params, TypeParam{Name: name.Name, Constraint: constraint})
+		}
+	}
+
+	return
+}
+
+// newMethod returns an interface method with the parameters and results of its function type.
+func newMethod(fset *token.FileSet, name string, typ *ast.FuncType) Method {
+	return Method{
+		Name:       name,
+		Definition: name + strings.TrimPrefix(printNode(fset, typ), "func"),
+		Params:     parseParams(fset, typ.Params),
+		Results:    parseParams(fset, typ.Results),
+	}
+}
+
+// parseParams returns one Param for each name in a parameter or result list. Unnamed parameters have an empty name.
+func parseParams(fset *token.FileSet, list *ast.FieldList) (params []Param) {
+	if list == nil {
+		return
+	}
+
+	for _, field := range list.List {
+		_, isVariadic := field.Type.(*ast.Ellipsis)
+		typ := printNode(fset, field.Type)
+		if len(field.Names) == 0 {
+			params = append(params, Param{Type: typ, IsVariadic: isVariadic})
+			continue
+		}
+		for _, name := range field.Names {
+			params = append(params, Param{Name: name.Name, Type: typ, IsVariadic: isVariadic})
+		}
+	}
+
+	return
+}
+
+// source returns the position of a declaration in its file.
+func (p *astParser) source(node ast.Node) Source {
+	start := p.fset.Position(node.Pos())
+	end := p.fset.Position(node.End())
+
+	return Source{File: start.Filename, Line: start.Line, EndLine: end.Line}
+}
+
+func (p *astParser) print(node interface{}) string {
+	return printNode(p.fset, node)
+}
+
+func printNode(fset *token.FileSet, node interface{}) string {
+	var buf bytes.Buffer
+	if err := (&printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}).Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+
+	return buf.String()
+}
+
+// printWithComments prints a node including the comments inside of it, like the field comments of a struct.
+func (p *astParser) printWithComments(node ast.Node) string {
+	for _, file := range p.files {
+		if p.fset.File(file.Pos()) == p.fset.File(node.Pos()) {
+			return p.print(&printer.CommentedNode{Node: node, Comments: file.Comments})
+		}
+	}
+
+	return p.print(node)
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// loadTestPackage writes the files into a temporary module and loads its package with GetPackage.
+func loadTestPackage(t *testing.T, files map[string]string) Package {
+	t.Helper()
+
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/example\n\ngo 1.21\n"
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pkg, err := GetPackage(dir, LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pkg
+}
+
+func TestMultiNameSpecInBlock(t *testing.T) {
+	pkg := loadTestPackage(t, map[string]string{"example.go": `package example
+
+var (
+	X, Y = 1, 2
+	A, B int
+	N, M = split()
+)
+
+func split() (int, int) { return 0, 0 }
+`})
+
+	if len(pkg.VariableBlocks) != 1 {
+		t.Fatalf("got %d variable blocks, want 1", len(pkg.VariableBlocks))
+	}
+	block := pkg.VariableBlocks[0]
+
+	wantDefinition := "X, Y = 1, 2\nA, B int\nN, M = split()"
+	if block.Definition != wantDefinition {
+		t.Errorf("block definition = %q, want %q", block.Definition, wantDefinition)
+	}
+
+	wantVariables := []struct {
+		name       string
+		definition string
+	}{
+		{"X", "X = 1"},
+		{"Y", "Y = 2"},
+		{"A", "A int"},
+		{"B", "B int"},
+		{"N", "N, M = split()"},
+		{"M", "N, M = split()"},
+	}
+	if len(block.Variables) != len(wantVariables) {
+		t.Fatalf("got %d variables, want %d", len(block.Variables), len(wantVariables))
+	}
+	for i, want := range wantVariables {
+		v := block.Variables[i]
+		if v.Name != want.name || v.Definition != want.definition {
+			t.Errorf("variable %d = %q with definition %q, want %q with definition %q", i, v.Name, v.Definition, want.name, want.definition)
+		}
+	}
+}
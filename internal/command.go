@@ -0,0 +1,557 @@
+package internal
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"path"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	cobraPath = "github.com/spf13/cobra"
+	pflagPath = "github.com/spf13/pflag"
+	flagPath  = "flag"
+)
+
+// Command is the command line interface of a main package, which is found by a static analysis of the
+// definitions of the flag package and of spf13/cobra commands.
+type Command struct {
+	Name string
+	// Path is the name of the command, prefixed with the names of its parent commands, like "gomark help".
+	Path  string
+	Use   string
+	Short string
+	Long  string
+	Flags []CommandFlag
+	// Commands are the subcommands of the command.
+	Commands []Command
+}
+
+// LocalFlags returns the flags, which are only available on the command itself.
+func (i Command) LocalFlags() (flags []CommandFlag) {
+	for _, f := range i.Flags {
+		if !f.IsPersistent {
+			flags = append(flags, f)
+		}
+	}
+
+	return
+}
+
+// PersistentFlags returns the flags, which are also available on the subcommands.
+func (i Command) PersistentFlags() (flags []CommandFlag) {
+	for _, f := range i.Flags {
+		if f.IsPersistent {
+			flags = append(flags, f)
+		}
+	}
+
+	return
+}
+
+// Usage returns the usage line of the command, which is Use prefixed with the names of the parent commands.
+func (i Command) Usage() string {
+	usage := i.Path
+	if i.Use != "" {
+		usage = strings.TrimSpace(strings.TrimSuffix(i.Path, i.Name) + i.Use)
+	}
+	if len(i.Flags) > 0 && !strings.Contains(usage, "[flags]") {
+		usage += " [flags]"
+	}
+
+	return usage
+}
+
+// CommandFlag is a flag of a Command. Default is the Go expression of the default value.
+type CommandFlag struct {
+	Name      string
+	Shorthand string
+	Type      string
+	Default   string
+	Usage     string
+	// IsPersistent reports whether the flag is inherited by subcommands.
+	IsPersistent bool
+	// IsPOSIX reports whether the flag is defined with spf13/pflag and uses the --name syntax.
+	IsPOSIX bool
+}
+
+func (i CommandFlag) String() string {
+	if !i.IsPOSIX {
+		return "-" + i.Name
+	}
+	if i.Shorthand != "" {
+		return "-" + i.Shorthand + ", --" + i.Name
+	}
+
+	return "--" + i.Name
+}
+
+// loadCommandPackages loads the packages of the module, which are imported by the main package, directly or
+// indirectly. The command definitions of CLIs are often placed in such a package, like "cmd".
+func loadCommandPackages(cfg *packages.Config, pkg *packages.Package) ([]*packages.Package, error) {
+	if pkg.Module == nil {
+		return []*packages.Package{pkg}, nil
+	}
+
+	result := []*packages.Package{pkg}
+	seen := map[string]bool{pkg.PkgPath: true}
+	queue := []*packages.Package{pkg}
+	for len(queue) > 0 {
+		var paths []string
+		for _, p := range queue {
+			for importPath := range p.Imports {
+				if !seen[importPath] && isInModule(importPath, pkg.Module.Path) {
+					seen[importPath] = true
+					paths = append(paths, importPath)
+				}
+			}
+		}
+		if len(paths) == 0 {
+			break
+		}
+
+		loaded, err := packages.Load(cfg, paths...)
+		if err != nil {
+			return nil, fmt.Errorf("error while loading the packages of command %q: %w", pkg.PkgPath, err)
+		}
+		result = append(result, loaded...)
+		queue = loaded
+	}
+
+	return result, nil
+}
+
+// commandParser collects the commands and flags, which are defined in a set of packages.
+// Commands are identified by the variables or functions, which hold or return them.
+type commandParser struct {
+	commands map[string]*Command
+	order    []string
+	children map[string][]string
+	returns  map[string]string
+	flagSets map[string]bool
+	// flags holds the flags, which are defined on the default flag set of the flag package.
+	flags []CommandFlag
+}
+
+// parseCommand analyzes the command line interface of a main package.
+func parseCommand(name string, pkgs []*packages.Package) *Command {
+	c := &commandParser{
+		commands: make(map[string]*Command),
+		children: make(map[string][]string),
+		returns:  make(map[string]string),
+		flagSets: make(map[string]bool),
+	}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			c.collectCommands(pkg, file)
+		}
+	}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			c.collectCalls(pkg, file)
+		}
+	}
+
+	isChild := make(map[string]bool)
+	for _, children := range c.children {
+		for _, child := range children {
+			isChild[child] = true
+		}
+	}
+
+	var root *Command
+	for _, key := range c.order {
+		if !isChild[key] && !c.flagSets[key] {
+			command := c.build(key, "", make(map[string]bool))
+			root = &command
+			break
+		}
+	}
+	if root == nil {
+		if len(c.flags) == 0 {
+			return nil
+		}
+		root = &Command{Name: name, Path: name}
+	}
+	root.Flags = append(root.Flags, c.flags...)
+
+	// Flag sets of the flag package with a constant name are subcommands, the others belong to the root command.
+	for _, key := range c.order {
+		command := c.commands[key]
+		switch {
+		case !c.flagSets[key] || isChild[key]:
+			continue
+		case command.Name == "":
+			root.Flags = append(root.Flags, command.Flags...)
+		default:
+			root.Commands = append(root.Commands, c.build(key, root.Path, make(map[string]bool)))
+		}
+	}
+
+	return root
+}
+
+// build returns the command with the given key and its subcommands.
+func (c *commandParser) build(key, parentPath string, visited map[string]bool) Command {
+	visited[key] = true
+	command := *c.commands[key]
+	command.Path = strings.TrimSpace(parentPath + " " + command.Name)
+	c.commands[key].Path = command.Path
+
+	command.Commands = nil
+	for _, child := range c.children[key] {
+		if !visited[child] {
+			command.Commands = append(command.Commands, c.build(child, command.Path, visited))
+		}
+	}
+
+	return command
+}
+
+func (c *commandParser) add(key string, command *Command) {
+	if _, ok := c.commands[key]; !ok {
+		c.order = append(c.order, key)
+	}
+	c.commands[key] = command
+}
+
+// collectCommands finds the cobra commands and the flag sets, which are assigned to variables or returned by functions.
+func (c *commandParser) collectCommands(pkg *packages.Package, file *ast.File) {
+	info := pkg.TypesInfo
+	define := func(lhs, rhs ast.Expr) {
+		ident, ok := lhs.(*ast.Ident)
+		if !ok {
+			return
+		}
+		obj := info.Defs[ident]
+		if obj == nil {
+			obj = info.Uses[ident]
+		}
+		if obj == nil {
+			return
+		}
+		if command := parseCobraCommand(info, rhs); command != nil {
+			c.add(objectKey(obj), command)
+		} else if name, ok := flagSetName(info, rhs); ok {
+			c.add(objectKey(obj), &Command{Name: name, Use: name})
+			c.flagSets[objectKey(obj)] = true
+		}
+	}
+
+	for _, decl := range file.Decls {
+		var fnKey string
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil {
+			if obj := info.Defs[fn.Name]; obj != nil {
+				fnKey = objectKey(obj)
+			}
+		}
+
+		ast.Inspect(decl, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.ValueSpec:
+				for i, name := range n.Names {
+					if i < len(n.Values) {
+						define(name, n.Values[i])
+					}
+				}
+			case *ast.AssignStmt:
+				if len(n.Lhs) == len(n.Rhs) {
+					for i := range n.Lhs {
+						define(n.Lhs[i], n.Rhs[i])
+					}
+				}
+			case *ast.FuncLit:
+				// Returns of function literals do not belong to the enclosing function.
+				return false
+			case *ast.ReturnStmt:
+				if fnKey == "" || len(n.Results) != 1 {
+					return true
+				}
+				if command := parseCobraCommand(info, n.Results[0]); command != nil {
+					c.add(fnKey, command)
+				} else if key, ok := c.commandKey(pkg, n.Results[0]); ok {
+					c.returns[fnKey] = key
+				}
+			}
+			return true
+		})
+	}
+}
+
+// collectCalls finds the subcommands added with AddCommand and the flag definitions.
+func (c *commandParser) collectCalls(pkg *packages.Package, file *ast.File) {
+	info := pkg.TypesInfo
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn := calledFunc(info, call)
+		if fn == nil || fn.Pkg() == nil {
+			return true
+		}
+		sig := fn.Type().(*types.Signature)
+		sel, _ := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+
+		switch {
+		case fn.Pkg().Path() == cobraPath && fn.Name() == "AddCommand" && sel != nil:
+			parent, ok := c.commandKey(pkg, sel.X)
+			if !ok {
+				return true
+			}
+			for _, arg := range call.Args {
+				if child, ok := c.commandKey(pkg, arg); ok {
+					c.children[parent] = append(c.children[parent], child)
+				}
+			}
+		case fn.Pkg().Path() == pflagPath && sig.Recv() != nil && sel != nil:
+			// cmd.Flags().String(...) or cmd.PersistentFlags().String(...)
+			flagsCall, ok := ast.Unparen(sel.X).(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			flagsFunc := calledFunc(info, flagsCall)
+			flagsSel, _ := ast.Unparen(flagsCall.Fun).(*ast.SelectorExpr)
+			if flagsFunc == nil || flagsFunc.Pkg() == nil || flagsFunc.Pkg().Path() != cobraPath || flagsSel == nil {
+				return true
+			}
+			key, ok := c.commandKey(pkg, flagsSel.X)
+			if !ok {
+				return true
+			}
+			if flag, ok := parseFlagCall(pkg, sig, call); ok {
+				flag.IsPOSIX = true
+				flag.IsPersistent = flagsFunc.Name() == "PersistentFlags"
+				c.commands[key].Flags = append(c.commands[key].Flags, flag)
+			}
+		case fn.Pkg().Path() == flagPath:
+			flag, ok := parseFlagCall(pkg, sig, call)
+			if !ok {
+				return true
+			}
+			if sig.Recv() == nil {
+				c.flags = append(c.flags, flag)
+			} else if sel != nil {
+				if key, ok := c.commandKey(pkg, sel.X); ok {
+					c.commands[key].Flags = append(c.commands[key].Flags, flag)
+				} else if isCommandLine(info, sel.X) {
+					c.flags = append(c.flags, flag)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// commandKey returns the key of the command, which an expression refers to.
+func (c *commandParser) commandKey(pkg *packages.Package, expr ast.Expr) (string, bool) {
+	info := pkg.TypesInfo
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		if obj := info.Uses[e]; obj != nil {
+			_, ok := c.commands[objectKey(obj)]
+			return objectKey(obj), ok
+		}
+	case *ast.SelectorExpr:
+		if obj := info.Uses[e.Sel]; obj != nil {
+			_, ok := c.commands[objectKey(obj)]
+			return objectKey(obj), ok
+		}
+	case *ast.CallExpr:
+		if fn := calledFunc(info, e); fn != nil {
+			key := objectKey(fn)
+			if returned, ok := c.returns[key]; ok {
+				return returned, true
+			}
+			_, ok := c.commands[key]
+			return key, ok
+		}
+	}
+
+	// Commands, which are passed to AddCommand as literals, have no variable.
+	if command := parseCobraCommand(info, expr); command != nil {
+		key := pkg.PkgPath + "@" + pkg.Fset.Position(expr.Pos()).String()
+		c.add(key, command)
+		return key, true
+	}
+
+	return "", false
+}
+
+// objectKey identifies an object across packages, which were loaded separately.
+func objectKey(obj types.Object) string {
+	if obj.Pkg() == nil {
+		return obj.Name()
+	}
+	if obj.Parent() == obj.Pkg().Scope() || obj.Parent() == nil {
+		return obj.Pkg().Path() + "." + obj.Name()
+	}
+
+	return fmt.Sprintf("%s.%s@%d", obj.Pkg().Path(), obj.Name(), obj.Pos())
+}
+
+func calledFunc(info *types.Info, call *ast.CallExpr) *types.Func {
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		fn, _ := info.Uses[fun].(*types.Func)
+		return fn
+	case *ast.SelectorExpr:
+		fn, _ := info.Uses[fun.Sel].(*types.Func)
+		return fn
+	}
+
+	return nil
+}
+
+// parseCobraCommand parses a cobra.Command or &cobra.Command composite literal.
+func parseCobraCommand(info *types.Info, expr ast.Expr) *Command {
+	expr = ast.Unparen(expr)
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok || !isNamed(info.TypeOf(lit), cobraPath, "Command") {
+		return nil
+	}
+
+	var command Command
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		switch key.Name {
+		case "Use":
+			command.Use = stringValue(info, kv.Value)
+		case "Short":
+			command.Short = stringValue(info, kv.Value)
+		case "Long":
+			command.Long = strings.TrimSpace(stringValue(info, kv.Value))
+		}
+	}
+	command.Name = strings.Fields(command.Use + " ")[0]
+
+	return &command
+}
+
+// flagSetName returns the name of a flag set created with flag.NewFlagSet. The name is empty, if it is not constant,
+// like os.Args[0].
+func flagSetName(info *types.Info, expr ast.Expr) (string, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return "", false
+	}
+	fn := calledFunc(info, call)
+	if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != flagPath || fn.Name() != "NewFlagSet" {
+		return "", false
+	}
+
+	if tv, ok := info.Types[call.Args[0]]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+		return constant.StringVal(tv.Value), true
+	}
+
+	return "", true
+}
+
+func isCommandLine(info *types.Info, expr ast.Expr) bool {
+	sel, ok := ast.Unparen(expr).(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	obj := info.Uses[sel.Sel]
+
+	return obj != nil && obj.Pkg() != nil && obj.Pkg().Path() == flagPath && obj.Name() == "CommandLine"
+}
+
+// parseFlagCall parses a call, which defines a flag, like String(name, value, usage) or
+// BoolVarP(p, name, shorthand, value, usage). The arguments are identified by the parameter names of the function.
+func parseFlagCall(pkg *packages.Package, sig *types.Signature, call *ast.CallExpr) (CommandFlag, bool) {
+	params := sig.Params()
+	if sig.Variadic() || params.Len() != len(call.Args) {
+		return CommandFlag{}, false
+	}
+
+	var flag CommandFlag
+	var hasName, hasUsage bool
+	for i := 0; i < params.Len(); i++ {
+		param := params.At(i)
+		arg := call.Args[i]
+		switch param.Name() {
+		case "name":
+			flag.Name, hasName = stringValue(pkg.TypesInfo, arg), true
+		case "shorthand":
+			flag.Shorthand = stringValue(pkg.TypesInfo, arg)
+		case "usage":
+			flag.Usage, hasUsage = stringValue(pkg.TypesInfo, arg), true
+		case "value":
+			// Var(value Value, name, usage) passes the flag.Value instead of a default value.
+			if _, ok := param.Type().Underlying().(*types.Interface); ok {
+				flag.Type = typeName(pkg.TypesInfo.TypeOf(arg))
+				continue
+			}
+			flag.Type = typeName(param.Type())
+			flag.Default = valueString(pkg, arg)
+		case "p":
+			if ptr, ok := param.Type().(*types.Pointer); ok && flag.Type == "" {
+				flag.Type = typeName(ptr.Elem())
+			}
+		}
+	}
+	if !hasName || !hasUsage {
+		return CommandFlag{}, false
+	}
+	if flag.Type == "" && sig.Results().Len() == 1 {
+		if ptr, ok := sig.Results().At(0).Type().(*types.Pointer); ok {
+			flag.Type = typeName(ptr.Elem())
+		}
+	}
+
+	return flag, true
+}
+
+func typeName(t types.Type) string {
+	if t == nil {
+		return ""
+	}
+
+	return types.TypeString(t, func(pkg *types.Package) string {
+		return path.Base(pkg.Path())
+	})
+}
+
+func isNamed(t types.Type, pkgPath, name string) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == pkgPath && named.Obj().Name() == name
+}
+
+// stringValue returns the value of a constant string expression, or the expression itself, if it is not constant.
+func stringValue(info *types.Info, expr ast.Expr) string {
+	if tv, ok := info.Types[expr]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+		return constant.StringVal(tv.Value)
+	}
+
+	return types.ExprString(expr)
+}
+
+// valueString returns the value of a constant expression, or the expression itself, if it is not constant.
+func valueString(pkg *packages.Package, expr ast.Expr) string {
+	if tv, ok := pkg.TypesInfo.Types[expr]; ok && tv.Value != nil {
+		return constantString(tv.Value)
+	}
+
+	return printNode(pkg.Fset, expr)
+}
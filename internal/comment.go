@@ -0,0 +1,205 @@
+package internal
+
+import (
+	"go/doc/comment"
+	"strings"
+)
+
+// DocFormatter converts the text of a doc comment into another format.
+// headingLevel is the level, which headings inside the doc comment should get.
+type DocFormatter func(text string, headingLevel int) string
+
+// FormatDocs replaces the doc comments of the package and its declarations with their formatted version.
+// Docs, which are rendered inside of table cells, like the docs of struct fields, are left as plain text.
+// level is the level of the top heading of the template. The headings inside the docs are nested below the headings
+// of the template, which start at level for the package, and are limited to level six.
+func (p *Package) FormatDocs(format DocFormatter, level int) {
+	offset := level - 1
+	limited := format
+	format = func(text string, headingLevel int) string {
+		return limited(text, min(headingLevel+offset, 6))
+	}
+
+	p.Doc = format(p.Doc, 2)
+	formatExamples(p.Examples, format, 4)
+	for _, notes := range p.Notes {
+		for i := range notes {
+			notes[i].Body = format(notes[i].Body, 4)
+		}
+	}
+
+	formatVariables(p.Constants, format, 4)
+	formatVariableBlocks(p.ConstantBlocks, format, 4)
+	formatVariables(p.Variables, format, 4)
+	formatVariableBlocks(p.VariableBlocks, format, 4)
+	formatFunctions(p.Functions, format, 4)
+
+	for i := range p.Types {
+		t := &p.Types[i]
+		t.Doc = format(t.Doc, 4)
+		if t.Enum != nil {
+			t.Enum.Doc = format(t.Enum.Doc, 5)
+		}
+		t.Associated.formatDocs(format)
+		formatFunctions(t.Functions, format, 5)
+		formatExamples(t.Examples, format, 5)
+	}
+
+	for i := range p.Structs {
+		s := &p.Structs[i]
+		s.Doc = format(s.Doc, 4)
+		s.Associated.formatDocs(format)
+		formatFunctions(s.Functions, format, 5)
+		formatExamples(s.Examples, format, 5)
+	}
+
+	for i := range p.Interfaces {
+		in := &p.Interfaces[i]
+		in.Doc = format(in.Doc, 4)
+		in.Associated.formatDocs(format)
+		for j := range in.Methods {
+			in.Methods[j].Doc = format(in.Methods[j].Doc, 5)
+		}
+		formatExamples(in.Examples, format, 5)
+	}
+}
+
+func (i *Associated) formatDocs(format DocFormatter) {
+	formatFunctions(i.Constructors, format, 5)
+	formatVariables(i.Constants, format, 5)
+	formatVariableBlocks(i.ConstantBlocks, format, 5)
+	formatVariables(i.Variables, format, 5)
+	formatVariableBlocks(i.VariableBlocks, format, 5)
+}
+
+func formatFunctions(functions []Function, format DocFormatter, headingLevel int) {
+	for i := range functions {
+		functions[i].Doc = format(functions[i].Doc, headingLevel)
+		formatExamples(functions[i].Examples, format, headingLevel)
+	}
+}
+
+func formatVariables(variables []Variable, format DocFormatter, headingLevel int) {
+	for i := range variables {
+		variables[i].Doc = format(variables[i].Doc, headingLevel)
+	}
+}
+
+// formatVariableBlocks formats the docs of the blocks. The docs of the variables inside the blocks are rendered as
+// table cells, so they stay plain text.
+func formatVariableBlocks(blocks []VariableBlock, format DocFormatter, headingLevel int) {
+	for i := range blocks {
+		blocks[i].Doc = format(blocks[i].Doc, headingLevel)
+	}
+}
+
+func formatExamples(examples []Example, format DocFormatter, headingLevel int) {
+	for i := range examples {
+		examples[i].Doc = format(examples[i].Doc, headingLevel)
+	}
+}
+
+// MarkdownDoc converts a Go doc comment into markdown.
+// Headings, lists and code blocks are converted to their markdown equivalent and paragraphs are reflowed into single lines.
+// Doc links are resolved into markdown links.
+func (l *DocLinks) MarkdownDoc(text string, headingLevel int) string {
+	if strings.TrimSpace(text) == "" {
+		return ""
+	}
+
+	parser := comment.Parser{
+		LookupPackage: l.lookupPackage,
+		LookupSym:     l.lookupSym,
+	}
+	d := parser.Parse(text)
+
+	var out strings.Builder
+	for i, block := range d.Content {
+		if i > 0 {
+			out.WriteString("\n\n")
+		}
+		l.writeMarkdownBlock(&out, block, headingLevel)
+	}
+
+	return out.String()
+}
+
+func (l *DocLinks) writeMarkdownBlock(out *strings.Builder, block comment.Block, headingLevel int) {
+	switch b := block.(type) {
+	case *comment.Heading:
+		out.WriteString(strings.Repeat("#", headingLevel) + " ")
+		l.writeMarkdownText(out, b.Text)
+	case *comment.Paragraph:
+		l.writeMarkdownText(out, b.Text)
+	case *comment.Code:
+		out.WriteString("```go\n")
+		out.WriteString(b.Text)
+		out.WriteString("```")
+	case *comment.List:
+		for i, item := range b.Items {
+			if i > 0 {
+				out.WriteString("\n")
+				if b.BlankBetween() {
+					out.WriteString("\n")
+				}
+			}
+
+			marker := "- "
+			if item.Number != "" {
+				marker = item.Number + ". "
+			}
+			out.WriteString(marker)
+
+			indent := strings.Repeat(" ", len(marker))
+			for j, content := range item.Content {
+				if j > 0 {
+					out.WriteString("\n\n" + indent)
+				}
+				var itemOut strings.Builder
+				l.writeMarkdownBlock(&itemOut, content, headingLevel)
+				out.WriteString(strings.ReplaceAll(itemOut.String(), "\n", "\n"+indent))
+			}
+		}
+	}
+}
+
+func (l *DocLinks) writeMarkdownText(out *strings.Builder, text []comment.Text) {
+	for _, t := range text {
+		switch t := t.(type) {
+		case comment.Plain:
+			out.WriteString(escapeMarkdown(string(t)))
+		case comment.Italic:
+			out.WriteString("*" + escapeMarkdown(string(t)) + "*")
+		case *comment.Link:
+			out.WriteString("[")
+			l.writeMarkdownText(out, t.Text)
+			out.WriteString("](" + t.URL + ")")
+		case *comment.DocLink:
+			url, ok := l.resolve(t)
+			if !ok {
+				l.writeMarkdownText(out, t.Text)
+				continue
+			}
+			out.WriteString("[")
+			l.writeMarkdownText(out, t.Text)
+			out.WriteString("](" + url + ")")
+		}
+	}
+}
+
+// escapeMarkdown escapes characters, which have a special meaning in markdown, and joins lines with spaces.
+func escapeMarkdown(s string) string {
+	var out strings.Builder
+	for _, r := range s {
+		switch r {
+		case '\n':
+			out.WriteRune(' ')
+		case '`', '_', '*', '[', ']', '<', '\\':
+			out.WriteString(`\` + string(r))
+		default:
+			out.WriteRune(r)
+		}
+	}
+
+	return out.String()
+}
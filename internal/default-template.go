@@ -9,3 +9,41 @@ type Pkg doc.Package
 
 //go:embed default.tmpl.md
 var DefaultMarkdownTemplate string
+
+//go:embed compact.tmpl.md
+var compactMarkdownTemplate string
+
+//go:embed readme-section.tmpl.md
+var readmeSectionMarkdownTemplate string
+
+//go:embed index.tmpl.md
+var DefaultIndexTemplate string
+
+//go:embed default.tmpl.html
+var DefaultHTMLTemplate string
+
+//go:embed index.tmpl.html
+var DefaultHTMLIndexTemplate string
+
+// Templates are the built-in templates, which can be selected by their name.
+// The "compact" template leaves out examples, tables and badges and the "readme-section" template renders only the
+// API reference with headings, which start at level two, to be embedded into an existing README.
+// All of them render their sections in the blocks "header", "toc", "constants", "variables", "functions", "types",
+// "structs" and "interfaces", which can be overridden with ParsePartials.
+var Templates = map[string]string{
+	"default":        DefaultMarkdownTemplate,
+	"compact":        compactMarkdownTemplate,
+	"readme-section": readmeSectionMarkdownTemplate,
+}
+
+// HeadingLevels are the default levels of the top headings of the built-in templates, which do not start at level one.
+// The templates write their headings with the heading function, so they can be shifted as a whole.
+var HeadingLevels = map[string]int{
+	"readme-section": 2,
+}
+
+// HTMLTemplates are the built-in html/templates, which can be selected by their name.
+// They define the same blocks as Templates, which can be overridden with ParseHTMLPartials.
+var HTMLTemplates = map[string]string{
+	"default": DefaultHTMLTemplate,
+}
@@ -0,0 +1,238 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"go/doc/comment"
+	"path"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// DefaultExternalLinkURL is the URL pattern for doc links to packages outside of the module.
+const DefaultExternalLinkURL = "https://pkg.go.dev/{{.ImportPath}}{{with .Symbol}}#{{.}}{{end}}"
+
+// DefaultModuleLinkURL is the URL pattern for doc links to other packages of the same module.
+const DefaultModuleLinkURL = "{{.RelPath}}/README.md{{with .Anchor}}#{{.}}{{end}}"
+
+// DocLinkTarget is passed to the URL patterns, when a doc link to another package is resolved.
+type DocLinkTarget struct {
+	ImportPath string
+	// RelPath is the path of the linked package relative to the documented package.
+	// It is only set for packages of the same module.
+	RelPath string
+	// Symbol is the linked symbol in the form "Name" or "Type.Name". It is empty for links to a package.
+	Symbol string
+	// Anchor is the anchor of Symbol in the docs generated by the built-in templates.
+	Anchor string
+}
+
+// DocLinks resolves the doc links of a package, which point to symbols, methods or other packages, into markdown links.
+// Links to symbols of the package point to the anchors of the built-in templates.
+// Links, which could not be resolved, are collected in Warnings.
+type DocLinks struct {
+	Warnings []string
+
+	pkg         *Package
+	anchors     map[string]string
+	moduleURL   *template.Template
+	externalURL *template.Template
+	warned      map[string]bool
+	// htmlDocs holds the output of HTMLDoc, which the doc template function passes through as HTML.
+	htmlDocs map[string]bool
+}
+
+// NewDocLinks returns a DocLinks for pkg, which uses the URL patterns moduleURL and externalURL for links to other packages.
+// The patterns are text/templates, which are executed with a DocLinkTarget.
+func NewDocLinks(pkg *Package, moduleURL, externalURL string) (*DocLinks, error) {
+	l := &DocLinks{pkg: pkg, anchors: pkg.symbolAnchors(), warned: make(map[string]bool)}
+
+	var err error
+	l.moduleURL, err = template.New("module-link-url").Parse(moduleURL)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing module link URL: %w", err)
+	}
+	l.externalURL, err = template.New("link-url").Parse(externalURL)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing link URL: %w", err)
+	}
+
+	return l, nil
+}
+
+func (l *DocLinks) lookupPackage(name string) (string, bool) {
+	if name == l.pkg.Name && l.pkg.ImportPath != "" {
+		return l.pkg.ImportPath, true
+	}
+	if importPath, ok := l.pkg.imports[name]; ok {
+		return importPath, true
+	}
+
+	// Unknown names are not reported, as lowercase words in brackets, like [n] or [i], are common in docs.
+	return comment.DefaultLookupPackage(name)
+}
+
+func (l *DocLinks) lookupSym(recv, name string) bool {
+	symbol := name
+	if recv != "" {
+		symbol = recv + "." + name
+	}
+	if _, ok := l.anchors[symbol]; ok {
+		return true
+	}
+
+	l.warn(fmt.Sprintf("doc link [%s] does not match a symbol of package %s", symbol, l.pkg.Name))
+	return false
+}
+
+func (l *DocLinks) warn(warning string) {
+	if !l.warned[warning] {
+		l.warned[warning] = true
+		l.Warnings = append(l.Warnings, warning)
+	}
+}
+
+// resolve returns the URL of a doc link.
+func (l *DocLinks) resolve(link *comment.DocLink) (string, bool) {
+	symbol := link.Name
+	if link.Recv != "" {
+		symbol = link.Recv + "." + link.Name
+	}
+
+	if link.ImportPath == "" || link.ImportPath == l.pkg.ImportPath {
+		if symbol == "" {
+			return "#" + headingAnchor(l.pkg.Name), true
+		}
+		anchor, ok := l.anchors[symbol]
+		if !ok {
+			l.warn(fmt.Sprintf("doc link [%s] does not match a symbol of package %s", symbol, l.pkg.Name))
+		}
+		return "#" + anchor, ok
+	}
+
+	target := DocLinkTarget{ImportPath: link.ImportPath, Symbol: symbol}
+	target.Anchor = symbol
+	pattern := l.externalURL
+	if l.pkg.ModulePath != "" && l.pkg.ImportPath != "" && isInModule(link.ImportPath, l.pkg.ModulePath) {
+		target.RelPath = relativeImportPath(l.pkg.ImportPath, link.ImportPath)
+		pattern = l.moduleURL
+	}
+
+	var url bytes.Buffer
+	if err := pattern.Execute(&url, target); err != nil {
+		l.warn(fmt.Sprintf("error while resolving doc link to %s: %s", link.ImportPath, err))
+		return "", false
+	}
+
+	return url.String(), true
+}
+
+func isInModule(importPath, modulePath string) bool {
+	return importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/")
+}
+
+// relativeImportPath returns the path of target relative to base, like "../other" for "mod/pkg" and "mod/other".
+func relativeImportPath(base, target string) string {
+	baseParts := strings.Split(base, "/")
+	targetParts := strings.Split(target, "/")
+
+	common := 0
+	for common < len(baseParts) && common < len(targetParts) && baseParts[common] == targetParts[common] {
+		common++
+	}
+
+	parts := []string{"."}
+	for range baseParts[common:] {
+		parts = append(parts, "..")
+	}
+	parts = append(parts, targetParts[common:]...)
+
+	return path.Join(parts...)
+}
+
+// symbolAnchors maps the symbols of the package, in the form "Name" or "Type.Name", to their anchors.
+// Like on pkg.go.dev, the templates name the anchors after the symbols, so they are unique and stay the same, when
+// symbols are added or removed. Struct fields, which have no anchor of their own, point to the anchor of the struct.
+func (p *Package) symbolAnchors() map[string]string {
+	anchors := make(map[string]string)
+	add := func(name string) {
+		if name == "_" || strings.HasSuffix(name, "._") {
+			return
+		}
+		anchors[name] = name
+	}
+	addValues := func(values []Variable, blocks []VariableBlock) {
+		for _, v := range values {
+			add(v.Name)
+		}
+		for _, b := range blocks {
+			for _, v := range b.Variables {
+				add(v.Name)
+			}
+		}
+	}
+	addAssociated := func(a Associated) {
+		addValues(a.Constants, a.ConstantBlocks)
+		addValues(a.Variables, a.VariableBlocks)
+		for _, f := range a.Constructors {
+			add(f.Name)
+		}
+	}
+	addMethods := func(typeName string, methods []Function) {
+		for _, f := range methods {
+			add(typeName + "." + f.Name)
+		}
+	}
+
+	addValues(p.Constants, p.ConstantBlocks)
+	addValues(p.Variables, p.VariableBlocks)
+	for _, f := range p.Functions {
+		add(f.Name)
+	}
+	for _, t := range p.Types {
+		add(t.Name)
+		if t.Enum != nil {
+			for _, v := range t.Enum.Values {
+				add(v.Name)
+			}
+		}
+		addAssociated(t.Associated)
+		addMethods(t.Name, t.Functions)
+	}
+	for _, s := range p.Structs {
+		add(s.Name)
+		for _, f := range s.Fields {
+			if f.Name == "_" {
+				continue
+			}
+			anchors[s.Name+"."+f.Name] = s.Name
+		}
+		addAssociated(s.Associated)
+		addMethods(s.Name, s.Functions)
+	}
+	for _, in := range p.Interfaces {
+		add(in.Name)
+		addAssociated(in.Associated)
+		for _, m := range in.Methods {
+			add(in.Name + "." + m.Name)
+		}
+	}
+
+	return anchors
+}
+
+// headingAnchor returns the anchor, which GitHub generates for a markdown heading.
+func headingAnchor(heading string) string {
+	var anchor strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ':
+			anchor.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			anchor.WriteRune(r)
+		}
+	}
+
+	return anchor.String()
+}
@@ -0,0 +1,275 @@
+package internal
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/doc"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// stringValues statically evaluates the String methods of the integer types in a package.
+// Supported are methods generated by stringer and methods, which switch over the receiver and return string literals.
+// The result maps type names to the String() output of each value.
+//
+// It has to be called before go/doc removes the unexported declarations from the syntax trees.
+func stringValues(pkg *packages.Package) map[string]map[int64]string {
+	result := make(map[string]map[int64]string)
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Name.Name != "String" || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Body == nil {
+				continue
+			}
+			if fn.Type.Params.NumFields() != 0 || fn.Type.Results.NumFields() != 1 {
+				continue
+			}
+
+			typeName := embeddedName(fn.Recv.List[0].Type)
+			values := stringerValues(pkg, typeName, fn)
+			if values == nil {
+				values = switchValues(pkg.TypesInfo, fn)
+			}
+			if len(values) > 0 {
+				result[typeName] = values
+			}
+		}
+	}
+
+	return result
+}
+
+// stringerValues evaluates a String method generated by stringer, which either slices the _T_name constant
+// with the _T_index array, or looks the value up in the _T_map map.
+func stringerValues(pkg *packages.Package, typeName string, fn *ast.FuncDecl) map[int64]string {
+	prefix := "_" + typeName + "_"
+
+	if lit := findVarValue(pkg.Syntax, prefix+"map"); lit != nil {
+		values := make(map[int64]string)
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				return nil
+			}
+			key, okKey := constInt(pkg.TypesInfo, kv.Key)
+			value, okValue := constString(pkg.TypesInfo, kv.Value)
+			if !okKey || !okValue {
+				return nil
+			}
+			values[key] = value
+		}
+		return values
+	}
+
+	name, ok := pkg.Types.Scope().Lookup(prefix + "name").(*types.Const)
+	if !ok || name.Val().Kind() != constant.String {
+		return nil
+	}
+	lit := findVarValue(pkg.Syntax, prefix+"index")
+	if lit == nil {
+		return nil
+	}
+
+	var index []int64
+	for _, elt := range lit.Elts {
+		i, ok := constInt(pkg.TypesInfo, elt)
+		if !ok {
+			return nil
+		}
+		index = append(index, i)
+	}
+
+	// Stringer subtracts the smallest value from the receiver, if it is not zero.
+	var offset int64
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok && len(assign.Rhs) == 1 {
+			if v, ok := constInt(pkg.TypesInfo, assign.Rhs[0]); ok {
+				switch assign.Tok {
+				case token.SUB_ASSIGN:
+					offset = v
+				case token.ADD_ASSIGN:
+					offset = -v
+				}
+			}
+		}
+		return true
+	})
+
+	names := constant.StringVal(name.Val())
+	values := make(map[int64]string)
+	for i := 0; i+1 < len(index); i++ {
+		if index[i] > index[i+1] || index[i+1] > int64(len(names)) {
+			return nil
+		}
+		values[int64(i)+offset] = names[index[i]:index[i+1]]
+	}
+
+	return values
+}
+
+// switchValues evaluates a String method, which switches over its receiver and returns a string literal for each case.
+func switchValues(info *types.Info, fn *ast.FuncDecl) map[int64]string {
+	recv := fn.Recv.List[0]
+	if len(recv.Names) != 1 {
+		return nil
+	}
+
+	values := make(map[int64]string)
+	for _, stmt := range fn.Body.List {
+		sw, ok := stmt.(*ast.SwitchStmt)
+		if !ok {
+			continue
+		}
+		if tag, ok := sw.Tag.(*ast.Ident); !ok || tag.Name != recv.Names[0].Name {
+			continue
+		}
+
+		for _, c := range sw.Body.List {
+			clause := c.(*ast.CaseClause)
+			if len(clause.Body) != 1 {
+				continue
+			}
+			ret, ok := clause.Body[0].(*ast.ReturnStmt)
+			if !ok || len(ret.Results) != 1 {
+				continue
+			}
+			s, ok := constString(info, ret.Results[0])
+			if !ok {
+				continue
+			}
+			for _, expr := range clause.List {
+				if v, ok := constInt(info, expr); ok {
+					values[v] = s
+				}
+			}
+		}
+	}
+
+	return values
+}
+
+// findVarValue returns the composite literal, which the package level variable with the given name is initialized with.
+func findVarValue(files []*ast.File, name string) *ast.CompositeLit {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.ValueSpec)
+				for i, ident := range spec.Names {
+					if ident.Name == name && i < len(spec.Values) {
+						lit, _ := spec.Values[i].(*ast.CompositeLit)
+						return lit
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func constInt(info *types.Info, expr ast.Expr) (int64, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil {
+		return 0, false
+	}
+
+	return constant.Int64Val(constant.ToInt(tv.Value))
+}
+
+func constString(info *types.Info, expr ast.Expr) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+
+	return constant.StringVal(tv.Value), true
+}
+
+// parseEnum returns the enum of an integer type, which consists of the constant blocks of the type that use iota.
+// The remaining constant declarations of the type are returned as they are.
+func (p *astParser) parseEnum(t *doc.Type, spec *ast.TypeSpec) (*Enum, []*doc.Value) {
+	obj := p.info.Defs[spec.Name]
+	if obj == nil {
+		return nil, t.Consts
+	}
+	if basic, ok := obj.Type().Underlying().(*types.Basic); !ok || basic.Info()&types.IsInteger == 0 {
+		return nil, t.Consts
+	}
+
+	var enum Enum
+	var rest []*doc.Value
+	for _, value := range t.Consts {
+		if !p.isEnumBlock(value.Decl, obj.Type()) {
+			rest = append(rest, value)
+			continue
+		}
+
+		if docs := strings.TrimSpace(value.Doc); docs != "" {
+			if enum.Doc != "" {
+				enum.Doc += "\n\n"
+			}
+			enum.Doc += docs
+		}
+		for _, spec := range value.Decl.Specs {
+			spec := spec.(*ast.ValueSpec)
+			for _, name := range spec.Names {
+				c, ok := p.info.Defs[name].(*types.Const)
+				if !ok || name.Name == "_" {
+					continue
+				}
+				v := EnumValue{
+					Name:  name.Name,
+					Value: c.Val().ExactString(),
+					Doc:   strings.TrimSpace(spec.Doc.Text()),
+				}
+				if v.Doc == "" {
+					v.Doc = strings.TrimSpace(spec.Comment.Text())
+				}
+				if i, ok := constant.Int64Val(c.Val()); ok {
+					v.String, v.HasString = p.stringValues[t.Name][i]
+				}
+				enum.Values = append(enum.Values, v)
+			}
+		}
+	}
+
+	if len(enum.Values) == 0 {
+		return nil, rest
+	}
+
+	return &enum, rest
+}
+
+// isEnumBlock reports whether a const declaration is a group, which uses iota and only declares constants of typ.
+func (p *astParser) isEnumBlock(decl *ast.GenDecl, typ types.Type) bool {
+	if !decl.Lparen.IsValid() {
+		return false
+	}
+
+	usesIota := false
+	for _, spec := range decl.Specs {
+		spec := spec.(*ast.ValueSpec)
+		for _, name := range spec.Names {
+			if obj := p.info.Defs[name]; obj == nil || !types.Identical(obj.Type(), typ) {
+				return false
+			}
+		}
+		for _, value := range spec.Values {
+			ast.Inspect(value, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok && ident.Name == "iota" {
+					usesIota = true
+				}
+				return !usesIota
+			})
+		}
+	}
+
+	return usesIota
+}
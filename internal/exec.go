@@ -1,15 +1,53 @@
 package internal
 
 import (
+	"encoding/json"
 	"fmt"
 	"os/exec"
 )
 
-func GetGoDoc(pkgPath string) (GoDoc, error) {
-	output, err := exec.Command("go", "doc", "-all", pkgPath).CombinedOutput()
+func GetGoDoc(pkgPath string, opts LoadOptions) (GoDoc, error) {
+	args := []string{"doc", "-all"}
+	if opts.Unexported {
+		args = append(args, "-u")
+	}
+	if len(opts.Tags) > 0 {
+		return GoDoc{}, fmt.Errorf("build tags are not supported by \"go doc\", use the ast extractor instead")
+	}
+
+	cmd := exec.Command("go", append(args, pkgPath)...)
+	cmd.Env = opts.env()
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return GoDoc{Raw: string(output)}, fmt.Errorf("error while running \"go doc\":\n%s", output)
+	}
+	godoc := GoDoc{Raw: string(output)}
+
+	// "go doc" does not print module information, so it is read with "go list".
+	cmd = exec.Command("go", "list", "-json", pkgPath)
+	cmd.Env = opts.env()
+	output, err = cmd.Output()
 	if err != nil {
-		return GoDoc{Raw: string(output)}, fmt.Errorf(`error while running "go doc":` + "\n" + string(output))
+		return godoc, fmt.Errorf("error while running \"go list\": %w", err)
+	}
+	var list struct {
+		Dir    string
+		Module *struct {
+			Path      string
+			Version   string
+			GoVersion string
+		}
+	}
+	err = json.Unmarshal(output, &list)
+	if err != nil {
+		return godoc, fmt.Errorf("error while reading the output of \"go list\": %w", err)
+	}
+	godoc.Package.Dir = list.Dir
+	if list.Module != nil {
+		godoc.Package.ModulePath = list.Module.Path
+		godoc.Package.ModuleVersion = list.Module.Version
+		godoc.Package.GoVersion = list.Module.GoVersion
 	}
 
-	return GoDoc{Raw: string(output)}, nil
+	return godoc, nil
 }
@@ -0,0 +1,204 @@
+package internal
+
+import (
+	"go/ast"
+	"go/doc"
+	"go/doc/comment"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// FuncMap returns the gomark functions for templates, which are available next to the sprig functions:
+//
+//   - anchor returns the anchor of a symbol, like "Name" or "Type.Name", or the anchor of a heading with the text.
+//   - linkType returns a type expression, like "map[string]*Type", as markdown with links to the types.
+//   - synopsis returns the first sentence of a doc comment.
+//   - shortSignature returns the name and the parameter and result types of a function definition, like "Name(int, string) error".
+//   - isExported reports whether a name, like "Name" or "Type.Name", is exported.
+//   - mdEscape escapes markdown characters and pipes, so the text can be used in paragraphs and table cells.
+//   - codeFence wraps code in a fenced code block with a language, like {{.Definition | codeFence "go"}}.
+//   - indentDoc indents all lines of a doc except the first, to nest it into a list item, like {{.Doc | indentDoc 2}}.
+//   - heading returns the markdown prefix of a heading, like {{heading 2}} for "##" at headingLevel 1.
+//
+// The anchors and links are resolved with links. If links is nil, which is the case while parsing a template,
+// anchor returns the anchor of a heading and linkType does not link anything. headingLevel is the level of the top
+// heading, which is rendered with {{heading 1}}.
+func FuncMap(links *DocLinks, headingLevel int) template.FuncMap {
+	return template.FuncMap{
+		"heading": func(level int) string {
+			return strings.Repeat("#", min(level+headingLevel-1, 6))
+		},
+		"anchor":         links.anchor,
+		"linkType":       links.linkType,
+		"synopsis":       synopsis,
+		"shortSignature": shortSignature,
+		"isExported":     isExported,
+		"mdEscape":       mdEscape,
+		"codeFence":      codeFence,
+		"indentDoc":      indentDoc,
+	}
+}
+
+func (l *DocLinks) anchor(text string) string {
+	if l != nil {
+		if anchor, ok := l.anchors[text]; ok {
+			return anchor
+		}
+	}
+
+	return headingAnchor(text)
+}
+
+var typeNamePattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?`)
+
+func (l *DocLinks) linkType(expr string) string {
+	var out strings.Builder
+	l.splitType(expr, func(text, url string) {
+		if url == "" {
+			out.WriteString(mdEscape(text))
+		} else {
+			out.WriteString("[" + mdEscape(text) + "](" + url + ")")
+		}
+	})
+
+	return out.String()
+}
+
+// splitType calls write with the parts of a type expression and the URLs of the type names among them.
+// The URL of the other parts is empty.
+func (l *DocLinks) splitType(expr string, write func(text, url string)) {
+	if l == nil {
+		write(expr, "")
+		return
+	}
+
+	last := 0
+	for _, match := range typeNamePattern.FindAllStringIndex(expr, -1) {
+		if match[0] > last {
+			write(expr[last:match[0]], "")
+		}
+		name := expr[match[0]:match[1]]
+		url, _ := l.typeURL(name)
+		write(name, url)
+		last = match[1]
+	}
+	if last < len(expr) {
+		write(expr[last:], "")
+	}
+}
+
+// typeURL returns the URL of a type name, like "Type" or "pkg.Type". Unknown names are not reported, as they are
+// mostly predeclared types or keywords.
+func (l *DocLinks) typeURL(name string) (string, bool) {
+	pkgName, symbol, qualified := strings.Cut(name, ".")
+	if !qualified {
+		if anchor, ok := l.anchors[name]; ok {
+			return "#" + anchor, true
+		}
+		return "", false
+	}
+
+	importPath, ok := l.lookupPackage(pkgName)
+	if !ok || importPath == l.pkg.ImportPath {
+		return "", false
+	}
+
+	return l.resolve(&comment.DocLink{ImportPath: importPath, Name: symbol})
+}
+
+func synopsis(text string) string {
+	return new(doc.Package).Synopsis(text)
+}
+
+// shortSignature shortens function definitions, like "func (t T) Name(a, b int) (err error)" or the definitions of
+// interface methods, to "Name(int, int) error". Other definitions are shortened to their first line.
+func shortSignature(definition string) string {
+	fset := token.NewFileSet()
+	var decl *ast.FuncDecl
+	for _, src := range []string{definition, "func " + definition} {
+		file, err := parser.ParseFile(fset, "", "package p\n"+src, 0)
+		if err == nil && len(file.Decls) == 1 {
+			decl, _ = file.Decls[0].(*ast.FuncDecl)
+			break
+		}
+	}
+	if decl == nil {
+		line, _, multiline := strings.Cut(strings.TrimSpace(definition), "\n")
+		if multiline && strings.HasSuffix(line, "{") {
+			line += " ... }"
+		}
+		return line
+	}
+
+	types := func(fields *ast.FieldList) []string {
+		var result []string
+		if fields == nil {
+			return result
+		}
+		for _, field := range fields.List {
+			t := printNode(fset, field.Type)
+			result = append(result, t)
+			for i := 1; i < len(field.Names); i++ {
+				result = append(result, t)
+			}
+		}
+		return result
+	}
+
+	signature := decl.Name.Name
+	if decl.Type.TypeParams != nil {
+		var names []string
+		for _, field := range decl.Type.TypeParams.List {
+			for _, name := range field.Names {
+				names = append(names, name.Name)
+			}
+		}
+		signature += "[" + strings.Join(names, ", ") + "]"
+	}
+	signature += "(" + strings.Join(types(decl.Type.Params), ", ") + ")"
+	switch results := types(decl.Type.Results); len(results) {
+	case 0:
+	case 1:
+		signature += " " + results[0]
+	default:
+		signature += " (" + strings.Join(results, ", ") + ")"
+	}
+
+	return signature
+}
+
+func isExported(name string) bool {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return token.IsExported(name)
+}
+
+func mdEscape(text string) string {
+	return strings.ReplaceAll(escapeMarkdown(text), "|", `\|`)
+}
+
+// codeFence uses a fence, which is longer than the longest run of backticks in code.
+func codeFence(lang, code string) string {
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+
+	return fence + lang + "\n" + strings.TrimSuffix(code, "\n") + "\n" + fence
+}
+
+func indentDoc(spaces int, text string) string {
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "" {
+			lines[i] = strings.Repeat(" ", spaces) + lines[i]
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,197 @@
+package internal
+
+import "testing"
+
+func testDocLinks(t *testing.T) *DocLinks {
+	t.Helper()
+
+	pkg := &Package{
+		Name:       "example",
+		ImportPath: "example.com/example",
+		Structs: []Struct{{
+			Name:      "Server",
+			Functions: []Function{{Name: "Run"}},
+		}},
+		imports: map[string]string{"io": "io"},
+	}
+	links, err := NewDocLinks(pkg, DefaultModuleLinkURL, DefaultExternalLinkURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return links
+}
+
+func TestShortSignature(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		want       string
+	}{
+		{"function", "func Print(a ...any) (n int, err error)", "Print(...any) (int, error)"},
+		{"method receiver", "func (s *Server) Run(addr string) error", "Run(string) error"},
+		{"generics", "func Map[K comparable, V any](m map[K]V) []V", "Map[K, V](map[K]V) []V"},
+		{"grouped params", "func Add(a, b int, c float64) int", "Add(int, int, float64) int"},
+		{"named results", "func Split(s string) (head, tail string)", "Split(string) (string, string)"},
+		{"interface method", "Read(p []byte) (n int, err error)", "Read([]byte) (int, error)"},
+		{"struct fallback", "type S struct {\n\tA int\n}", "type S struct { ... }"},
+		{"value fallback", "const Max = 10", "const Max = 10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shortSignature(tt.definition); got != tt.want {
+				t.Errorf("shortSignature(%q) = %q, want %q", tt.definition, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodeFence(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		code string
+		want string
+	}{
+		{"plain", "go", "x := 1\n", "```go\nx := 1\n```"},
+		{"contains fence", "md", "```go\nx\n```", "````md\n```go\nx\n```\n````"},
+		{"contains longer fence", "", "````", "`````\n````\n`````"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := codeFence(tt.lang, tt.code); got != tt.want {
+				t.Errorf("codeFence(%q, %q) = %q, want %q", tt.lang, tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMdEscape(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"plain text", "plain text"},
+		{"a | b", `a \| b`},
+		{"*bold* and [link]", `\*bold\* and \[link\]`},
+		{"line\nbreak", "line break"},
+	}
+
+	for _, tt := range tests {
+		if got := mdEscape(tt.text); got != tt.want {
+			t.Errorf("mdEscape(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestIndentDoc(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"single line", "single line"},
+		{"a\nb", "a\n  b"},
+		{"a\n\nb\n  \nc", "a\n\n  b\n  \n  c"},
+	}
+
+	for _, tt := range tests {
+		if got := indentDoc(2, tt.text); got != tt.want {
+			t.Errorf("indentDoc(2, %q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestIsExported(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Name", true},
+		{"name", false},
+		{"Type.Method", true},
+		{"Type.method", false},
+		{"_", false},
+	}
+
+	for _, tt := range tests {
+		if got := isExported(tt.name); got != tt.want {
+			t.Errorf("isExported(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSynopsis(t *testing.T) {
+	text := "Package example does things. It does them well."
+	if got, want := synopsis(text), "Package example does things."; got != want {
+		t.Errorf("synopsis(%q) = %q, want %q", text, got, want)
+	}
+}
+
+func TestAnchor(t *testing.T) {
+	links := testDocLinks(t)
+	tests := []struct {
+		name  string
+		links *DocLinks
+		text  string
+		want  string
+	}{
+		{"nil links symbol", nil, "Server.Run", "serverrun"},
+		{"nil links heading", nil, "Platform Availability", "platform-availability"},
+		{"resolved symbol", links, "Server.Run", "Server.Run"},
+		{"resolved heading", links, "Platform Availability", "platform-availability"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.links.anchor(tt.text); got != tt.want {
+				t.Errorf("anchor(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkType(t *testing.T) {
+	links := testDocLinks(t)
+	tests := []struct {
+		name  string
+		links *DocLinks
+		expr  string
+		want  string
+	}{
+		{"nil links", nil, "map[string]*Server", `map\[string\]\*Server`},
+		{"package type", links, "[]*Server", `\[\]\*[Server](#Server)`},
+		{"predeclared type", links, "map[string]int", `map\[string\]int`},
+		{"imported type", links, "io.Reader", "[io.Reader](https://pkg.go.dev/io#Reader)"},
+		{"unknown package", links, "foo.Bar", "foo.Bar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.links.linkType(tt.expr); got != tt.want {
+				t.Errorf("linkType(%q) = %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeading(t *testing.T) {
+	tests := []struct {
+		headingLevel int
+		level        int
+		want         string
+	}{
+		{1, 1, "#"},
+		{1, 3, "###"},
+		{2, 3, "####"},
+		{4, 5, "######"},
+	}
+
+	for _, tt := range tests {
+		heading := FuncMap(nil, tt.headingLevel)["heading"].(func(int) string)
+		if got := heading(tt.level); got != tt.want {
+			t.Errorf("heading(%d) at level %d = %q, want %q", tt.level, tt.headingLevel, got, tt.want)
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package internal
 
 func GenerateTestPackage() Package {
-	return Package{
+	pkg := Package{
 		Name: "experimenting",
 		Doc:  "Package experimenting is an experimenting package.\nThis is the package doc.",
 		Variables: []Variable{
@@ -21,7 +21,8 @@ func GenerateTestPackage() Package {
 					{Name: "ThisIsInsideTheFirstVariableBlock4", Doc: "ThisIsInsideTheFirstVariableBlock4 docs.", Definition: `ThisIsInsideTheFirstVariableBlock4 = ""`, Value: "", Type: ""},
 					{Name: "ThisIsInsideTheFirstVariableBlock5", Doc: "ThisIsInsideTheFirstVariableBlock5 docs.", Definition: `ThisIsInsideTheFirstVariableBlock5 = ""`, Value: "", Type: ""},
 				},
-				Doc: "docs.",
+				Definition: "ThisIsInsideTheFirstVariableBlock1 = \"\"\nThisIsInsideTheFirstVariableBlock2 = \"\"\nThisIsInsideTheFirstVariableBlock3 = \"\"\nThisIsInsideTheFirstVariableBlock4 = \"\"\nThisIsInsideTheFirstVariableBlock5 = \"\"",
+				Doc:        "docs.",
 			},
 			{
 				Variables: []Variable{
@@ -31,7 +32,8 @@ func GenerateTestPackage() Package {
 					{Name: "ThisIsInsideTheSecondVariableBlock4", Doc: "ThisIsInsideTheSecondVariableBlock4 docs.", Definition: `ThisIsInsideTheSecondVariableBlock4 = ""`, Value: "", Type: ""},
 					{Name: "ThisIsInsideTheSecondVariableBlock5", Doc: "ThisIsInsideTheSecondVariableBlock5 docs.", Definition: `ThisIsInsideTheSecondVariableBlock5 = ""`, Value: "", Type: ""},
 				},
-				Doc: "docs.",
+				Definition: "ThisIsInsideTheSecondVariableBlock1 = \"\"\nThisIsInsideTheSecondVariableBlock2 = \"\"\nThisIsInsideTheSecondVariableBlock3 = \"\"\nThisIsInsideTheSecondVariableBlock4 = \"\"\nThisIsInsideTheSecondVariableBlock5 = \"\"",
+				Doc:        "docs.",
 			},
 		},
 		Constants: []Variable{
@@ -47,7 +49,8 @@ func GenerateTestPackage() Package {
 					{Name: "ThisIsInsideTheFirstConstantBlock4", Doc: "ThisIsInsideTheFirstConstantBlock4 docs.", Definition: `ThisIsInsideTheFirstConstantBlock4 = ""`, Value: "", Type: ""},
 					{Name: "ThisIsInsideTheFirstConstantBlock5", Doc: "ThisIsInsideTheFirstConstantBlock5 docs.", Definition: `ThisIsInsideTheFirstConstantBlock5 = ""`, Value: "", Type: ""},
 				},
-				Doc: "",
+				Definition: "ThisIsInsideTheFirstConstantBlock1 = \"\"\nThisIsInsideTheFirstConstantBlock2 = \"\"\nThisIsInsideTheFirstConstantBlock3 = \"\"\nThisIsInsideTheFirstConstantBlock4 = \"\"\nThisIsInsideTheFirstConstantBlock5 = \"\"",
+				Doc:        "",
 			},
 			{
 				Variables: []Variable{
@@ -57,7 +60,8 @@ func GenerateTestPackage() Package {
 					{Name: "ThisIsInsideTheSecondConstantBlock4", Doc: "ThisIsInsideTheSecondConstantBlock4 docs.", Definition: `ThisIsInsideTheSecondConstantBlock4 = ""`, Value: "", Type: ""},
 					{Name: "ThisIsInsideTheSecondConstantBlock5", Doc: "ThisIsInsideTheSecondConstantBlock5 docs.", Definition: `ThisIsInsideTheSecondConstantBlock5 = ""`, Value: "", Type: ""},
 				},
-				Doc: "docs.",
+				Definition: "ThisIsInsideTheSecondConstantBlock1 = \"\"\nThisIsInsideTheSecondConstantBlock2 = \"\"\nThisIsInsideTheSecondConstantBlock3 = \"\"\nThisIsInsideTheSecondConstantBlock4 = \"\"\nThisIsInsideTheSecondConstantBlock5 = \"\"",
+				Doc:        "docs.",
 			},
 		},
 		Functions: []Function{
@@ -90,13 +94,16 @@ func GenerateTestPackage() Package {
 	Run(text string)
 	Get() string
 }`,
-				Values: []Variable{
-					{Name: "Run", Doc: "", Definition: "Run(text string)", Value: "", Type: ""},
-					{Name: "Get", Doc: "", Definition: "Get() string", Value: "", Type: ""},
+				Methods: []Method{
+					{Name: "Run", Doc: "", Definition: "Run(text string)", Params: []Param{{Name: "text", Type: "string"}}},
+					{Name: "Get", Doc: "", Definition: "Get() string", Results: []Param{{Type: "string"}}},
 				},
 			},
 		},
 	}
+	pkg.markExported()
+
+	return pkg
 }
 
 const TestGodoc = `package experimenting // import "github.com/MarvinJWendt/gomark/experimenting"
@@ -2,6 +2,10 @@ package internal
 
 import (
 	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
 	"strings"
 	"unicode"
 )
@@ -26,6 +30,10 @@ func (d *GoDoc) Parse() error {
 		if IsUpper(trimmedLine) {
 			currentSection = strings.ToLower(trimmedLine)
 		} else {
+			// Newer versions of "go doc" print BUG notes without a "BUGS" heading.
+			if strings.HasPrefix(line, "BUG: ") {
+				currentSection = "bugs"
+			}
 			d.Sections[currentSection] += line + "\n"
 		}
 	}
@@ -34,7 +42,16 @@ func (d *GoDoc) Parse() error {
 	docs := d.Sections["docs"]
 	lines = strings.Split(docs, "\n")
 	d.Package.Name = strings.Fields(lines[0])[1]
+	if _, importPath, found := strings.Cut(lines[0], "// import "); found {
+		d.Package.ImportPath = strings.Trim(importPath, `"`)
+	}
 	d.Package.Doc = strings.TrimSpace(strings.Join(lines[2:], "\n"))
+	d.Package.Synopsis = new(doc.Package).Synopsis(d.Package.Doc)
+
+	// Parse notes
+	if bugs := parseBugs(d.Sections["bugs"]); len(bugs) > 0 {
+		d.Package.Notes = map[string][]Note{"BUG": bugs}
+	}
 
 	// Parse function docs
 	docs = d.Sections["functions"]
@@ -43,10 +60,7 @@ func (d *GoDoc) Parse() error {
 
 	for _, line := range lines {
 		if getFunctionName(line) != "" {
-			funcs = append(funcs, Function{
-				Name:       getFunctionName(line),
-				Definition: strings.TrimSpace(line),
-			})
+			funcs = append(funcs, parseFunctionDefinition(strings.TrimSpace(line)))
 		} else if line != "" {
 			if len(funcs) > 0 {
 				funcs[len(funcs)-1].Doc += strings.TrimLeft(line, " ") + "\n"
@@ -90,6 +104,10 @@ func (d *GoDoc) Parse() error {
 			}
 
 			if isBlock {
+				// Comments inside of the block are not variables.
+				if s == "" || strings.HasPrefix(s, "//") {
+					continue
+				}
 				var block *VariableBlock
 
 				if short == "var" {
@@ -98,7 +116,7 @@ func (d *GoDoc) Parse() error {
 					block = d.Package.getLastConstantBlock()
 				}
 
-				block.Variables = append(block.Variables, parseVariable(line))
+				block.addVariable(line)
 			} else if strings.HasPrefix(s, "var") || strings.HasPrefix(s, "const") {
 				if short == "var" {
 					d.Package.Variables = append(d.Package.Variables, parseVariable(line))
@@ -120,6 +138,7 @@ func (d *GoDoc) Parse() error {
 	var lastFunc *Function
 	var lastType string
 	var lastDocumentable documentable
+	var lastValueBlock *VariableBlock
 
 	for _, line := range lines {
 		s := strings.TrimSpace(line)
@@ -138,27 +157,30 @@ func (d *GoDoc) Parse() error {
 			lastType = currentType
 		case "interface":
 			lastDefinition = &d.Package.getLastInterface().Definition
-			d.Package.getLastInterface().Values = append(d.Package.getLastInterface().Values, parseVariable(s))
+			d.Package.getLastInterface().addMember(s)
 			lastDocumentable = d.Package.getLastInterface()
 			*lastDefinition += line + "\n"
-		case "var":
-			lastDocumentable = d.Package.getLastVariable()
 
-			currentType = ""
+			lastType = currentType
+		case "valueBlock":
+			if strings.HasPrefix(line, ")") {
+				currentType = ""
+			} else if s != "" && !strings.HasPrefix(s, "//") {
+				lastValueBlock.addVariable(s)
+			}
+			continue
 		case "func":
 			if lastType == "struct" {
 				d.Package.getLastStruct().Functions = append(d.Package.getLastStruct().Functions, Function{})
 				lastFunc = &d.Package.getLastStruct().Functions[len(d.Package.getLastStruct().Functions)-1]
 				if lastDefinition != nil && *lastDefinition != "" {
-					lastFunc.Name = getFunctionName(*lastDefinition)
-					lastFunc.Definition = *lastDefinition
+					*lastFunc = parseFunctionDefinition(*lastDefinition)
 				}
 			} else if lastType == "type" {
 				d.Package.getLastType().Functions = append(d.Package.getLastType().Functions, Function{})
 				lastFunc = &d.Package.getLastType().Functions[len(d.Package.getLastType().Functions)-1]
 				if lastDefinition != nil && *lastDefinition != "" {
-					lastFunc.Name = getFunctionName(*lastDefinition)
-					lastFunc.Definition = *lastDefinition
+					*lastFunc = parseFunctionDefinition(*lastDefinition)
 				}
 			}
 
@@ -177,37 +199,56 @@ func (d *GoDoc) Parse() error {
 			switch {
 			case strings.Contains(line, "struct {"):
 				currentType = "struct"
+				name, typeParams := parseTypeHeader(line)
 				d.Package.Structs = append(d.Package.Structs, Struct{
-					Name:       strings.Split(line, " ")[1],
+					Name:       name,
 					Definition: line + "\n",
+					TypeParams: typeParams,
 				})
 				continue
 			case strings.Contains(line, "interface {"):
 				currentType = "interface"
+				name, typeParams := parseTypeHeader(line)
 				d.Package.Interfaces = append(d.Package.Interfaces, Interface{
-					Name:       strings.Split(line, " ")[1],
+					Name:       name,
 					Definition: line + "\n",
+					TypeParams: typeParams,
 				})
 				continue
 			default:
 				currentType = "type"
+				name, typeParams := parseTypeHeader(line)
 				d.Package.Types = append(d.Package.Types, Type{
-					Name:       strings.Split(line, " ")[1],
+					Name:       name,
 					Definition: line,
+					TypeParams: typeParams,
 				})
 			}
-		case strings.HasPrefix(line, "var"):
-			currentType = "var"
-			d.Package.Variables = append(d.Package.Variables, Variable{
-				Definition: line,
-			})
+		case strings.HasPrefix(line, "var ") || strings.HasPrefix(line, "const "):
+			currentType = ""
+			associated := d.Package.getLastAssociated(lastType)
+			if strings.HasSuffix(line, "(") {
+				currentType = "valueBlock"
+				lastValueBlock = associated.addBlock(line)
+				lastDocumentable = lastValueBlock
+			} else {
+				lastDocumentable = associated.addValue(line)
+			}
+			continue
+		case strings.HasPrefix(line, "func ") && !strings.HasPrefix(line, "func ("):
+			currentType = ""
+			associated := d.Package.getLastAssociated(lastType)
+			associated.Constructors = append(associated.Constructors, parseFunctionDefinition(line))
+			lastDocumentable = &associated.Constructors[len(associated.Constructors)-1]
 			continue
 		case strings.HasPrefix(line, "func ("):
 			currentType = "func"
 			if d.Package.getLastType() == nil {
 				d.Package.Types = append(d.Package.Types, Type{})
 			}
-			*lastDefinition = line
+			// The method is parsed from its definition, when the next line is read.
+			definition := line
+			lastDefinition = &definition
 			continue
 		case strings.HasPrefix(line, "    "):
 			currentType = "docs"
@@ -218,9 +259,42 @@ func (d *GoDoc) Parse() error {
 		}
 	}
 
+	d.Package.markExported()
+
 	return nil
 }
 
+// addValue adds a single var or const declaration, which "go doc" lists under a type.
+func (a *Associated) addValue(line string) documentable {
+	if strings.HasPrefix(line, "const") {
+		a.Constants = append(a.Constants, parseVariable(line))
+		return &a.Constants[len(a.Constants)-1]
+	}
+
+	a.Variables = append(a.Variables, parseVariable(line))
+	return &a.Variables[len(a.Variables)-1]
+}
+
+// addBlock adds an empty var or const block, which "go doc" lists under a type.
+func (a *Associated) addBlock(line string) *VariableBlock {
+	if strings.HasPrefix(line, "const") {
+		a.ConstantBlocks = append(a.ConstantBlocks, VariableBlock{})
+		return &a.ConstantBlocks[len(a.ConstantBlocks)-1]
+	}
+
+	a.VariableBlocks = append(a.VariableBlocks, VariableBlock{})
+	return &a.VariableBlocks[len(a.VariableBlocks)-1]
+}
+
+// addVariable adds a variable, which "go doc" lists on a line inside of the block.
+func (i *VariableBlock) addVariable(line string) {
+	i.Variables = append(i.Variables, parseVariable(line))
+	if i.Definition != "" {
+		i.Definition += "\n"
+	}
+	i.Definition += strings.TrimSpace(line)
+}
+
 func parseVariable(input string) (v Variable) {
 	if input == "" {
 		return
@@ -234,14 +308,71 @@ func parseVariable(input string) (v Variable) {
 	if input == "" {
 		return
 	}
-	if strings.Split(input, "")[0] == "=" {
-		strings.TrimSpace(strings.Join(strings.Split(input, " ")[0:], " "))
+	if i := strings.Index(input, "="); i >= 0 {
+		v.Type = strings.TrimSpace(input[:i])
+		v.Value = strings.TrimSpace(input[i+1:])
 	} else {
 		v.Type = strings.TrimSpace(input)
 	}
 	return
 }
 
+// addMember adds a line of an interface definition either as a method or as an embedded interface.
+// Comment lines are skipped, as "go doc" does not associate them with a member.
+func (i *Interface) addMember(line string) {
+	if line == "" || strings.HasPrefix(line, "//") {
+		return
+	}
+
+	if strings.HasPrefix(line, "~") || strings.Contains(line, "|") {
+		var u Union
+		for _, term := range strings.Split(line, "|") {
+			term = strings.TrimSpace(term)
+			u.Terms = append(u.Terms, Term{Type: strings.TrimPrefix(term, "~"), IsTilde: strings.HasPrefix(term, "~")})
+		}
+		i.Unions = append(i.Unions, u)
+		i.IsConstraint = true
+		return
+	}
+
+	name := strings.Split(line, "(")[0]
+	if name == line {
+		i.Embedded = append(i.Embedded, Field{Name: line, Type: line, IsEmbedded: true})
+		return
+	}
+
+	fset := token.NewFileSet()
+	expr, err := parser.ParseExprFrom(fset, "", "func"+strings.TrimPrefix(line, name), 0)
+	if typ, ok := expr.(*ast.FuncType); err == nil && ok {
+		i.Methods = append(i.Methods, newMethod(fset, name, typ))
+	} else {
+		i.Methods = append(i.Methods, Method{Name: name, Definition: line})
+	}
+}
+
+// parseBugs parses the BUG notes printed by "go doc". The notes are separated by empty lines and may start with
+// "BUG: " or, in older versions, with "☞ ".
+func parseBugs(section string) (bugs []Note) {
+	inNote := false
+	for _, line := range strings.Split(section, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			inNote = false
+			continue
+		}
+
+		line = strings.TrimPrefix(strings.TrimPrefix(line, "BUG: "), "☞ ")
+		if inNote {
+			bugs[len(bugs)-1].Body += "\n" + line
+		} else {
+			bugs = append(bugs, Note{Body: line})
+			inNote = true
+		}
+	}
+
+	return bugs
+}
+
 func IsUpper(s string) bool {
 	if s == "" {
 		return false
@@ -254,6 +385,45 @@ func IsUpper(s string) bool {
 	return true
 }
 
+// parseTypeHeader returns the name and the type parameters of the first line of a type definition,
+// like "type Set[T comparable] struct {".
+func parseTypeHeader(line string) (string, []TypeParam) {
+	src := "package p\n" + line
+	if strings.HasSuffix(line, "{") {
+		src += "}"
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, 0)
+	if err == nil && len(file.Decls) == 1 {
+		if decl, ok := file.Decls[0].(*ast.GenDecl); ok && len(decl.Specs) == 1 {
+			if spec, ok := decl.Specs[0].(*ast.TypeSpec); ok {
+				return spec.Name.Name, parseTypeParams(fset, spec.TypeParams)
+			}
+		}
+	}
+
+	fields := strings.FieldsFunc(strings.TrimPrefix(line, "type "), func(r rune) bool { return r == ' ' || r == '[' })
+	if len(fields) == 0 {
+		return "", nil
+	}
+
+	return fields[0], nil
+}
+
+// parseFunctionDefinition parses a function definition, like "func (s S) Get(key string) string", into a Function.
+func parseFunctionDefinition(input string) Function {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", "package p\n"+input, 0)
+	if err == nil && len(file.Decls) == 1 {
+		if decl, ok := file.Decls[0].(*ast.FuncDecl); ok {
+			return newFunction(fset, decl)
+		}
+	}
+
+	return Function{Name: getFunctionName(input), Definition: input}
+}
+
 func getFunctionName(input string) string {
 	if !strings.HasPrefix(input, "func ") {
 		return ""
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"os"
+	"testing"
+)
+
+func TestGoDocCommentedBlock(t *testing.T) {
+	raw, err := os.ReadFile("testdata/commented-block.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := GoDoc{Raw: string(raw)}
+	if err := d.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		blocks []VariableBlock
+		want   []string
+	}{
+		{"constants", d.Package.ConstantBlocks, []string{"Small", "Large"}},
+		{"variables", d.Package.VariableBlocks, []string{"First", "Second"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.blocks) != 1 {
+				t.Fatalf("got %d blocks, want 1", len(tt.blocks))
+			}
+			var names []string
+			for _, v := range tt.blocks[0].Variables {
+				names = append(names, v.Name)
+			}
+			if len(names) != len(tt.want) {
+				t.Fatalf("got variables %q, want %q", names, tt.want)
+			}
+			for i := range names {
+				if names[i] != tt.want[i] {
+					t.Errorf("got variables %q, want %q", names, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGoDocPlainTypeMethods(t *testing.T) {
+	raw, err := os.ReadFile("testdata/plain-type-methods.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := GoDoc{Raw: string(raw)}
+	if err := d.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range d.Package.Structs {
+		if s.Name == "Config" && s.Definition != "type Config struct {\n\t// Name is the name.\n\tName string\n}" {
+			t.Errorf("struct definition = %q, the methods must not replace it", s.Definition)
+		}
+	}
+	var methods []string
+	for _, typ := range d.Package.Types {
+		for _, f := range typ.Functions {
+			methods = append(methods, typ.Name+"."+f.Name)
+		}
+	}
+	for _, s := range d.Package.Structs {
+		for _, f := range s.Functions {
+			methods = append(methods, s.Name+"."+f.Name)
+		}
+	}
+	want := map[string]bool{"Apply.Serve": true, "Empty.Do": true, "Config.Validate": true}
+	for _, m := range methods {
+		delete(want, m)
+	}
+	if len(want) > 0 {
+		t.Errorf("got methods %q, missing %v", methods, want)
+	}
+}
@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"bytes"
+	"go/doc/comment"
+	htmltemplate "html/template"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// DefaultHTMLModuleLinkURL is the URL pattern for doc links to other packages of the same module in HTML docs.
+const DefaultHTMLModuleLinkURL = "{{.RelPath}}/index.html{{with .Anchor}}#{{.}}{{end}}"
+
+// highlighter renders Go code with CSS classes, so the colors can change with the theme of the page.
+var highlighter = chromahtml.New(chromahtml.WithClasses(true), chromahtml.WithCSSComments(false))
+
+// HTMLDoc converts a Go doc comment into HTML. Doc links are resolved like in MarkdownDoc.
+// Only the output of HTMLDoc is rendered as HTML by the doc template function.
+func (l *DocLinks) HTMLDoc(text string, headingLevel int) string {
+	if strings.TrimSpace(text) == "" {
+		return ""
+	}
+
+	parser := comment.Parser{
+		LookupPackage: l.lookupPackage,
+		LookupSym:     l.lookupSym,
+	}
+	printer := comment.Printer{
+		HeadingLevel: headingLevel,
+		DocLinkURL: func(link *comment.DocLink) string {
+			url, ok := l.resolve(link)
+			if !ok {
+				return ""
+			}
+			return url
+		},
+	}
+
+	html := string(printer.HTML(parser.Parse(text)))
+	if l.htmlDocs == nil {
+		l.htmlDocs = make(map[string]bool)
+	}
+	l.htmlDocs[html] = true
+
+	return html
+}
+
+// HTMLFuncMap returns the functions for HTML templates. Next to the functions of FuncMap, with linkType returning
+// HTML, there are:
+//
+//   - doc marks a doc comment, which was formatted with HTMLDoc, as safe HTML. Other text is escaped.
+//   - highlight returns Go code as syntax highlighted HTML.
+//   - highlightCSS returns the styles of the highlighted code for the light and the dark theme.
+func HTMLFuncMap(links *DocLinks) htmltemplate.FuncMap {
+	funcs := htmltemplate.FuncMap(FuncMap(links, 1))
+	funcs["linkType"] = links.linkTypeHTML
+	funcs["doc"] = links.doc
+	funcs["highlight"] = highlight
+	funcs["highlightCSS"] = highlightCSS
+
+	return funcs
+}
+
+func (l *DocLinks) doc(text string) htmltemplate.HTML {
+	if l != nil && l.htmlDocs[text] {
+		return htmltemplate.HTML(text)
+	}
+
+	return htmltemplate.HTML(htmltemplate.HTMLEscapeString(text))
+}
+
+func (l *DocLinks) linkTypeHTML(expr string) htmltemplate.HTML {
+	var out strings.Builder
+	l.splitType(expr, func(text, url string) {
+		if url == "" {
+			out.WriteString(htmltemplate.HTMLEscapeString(text))
+		} else {
+			out.WriteString(`<a href="` + htmltemplate.HTMLEscapeString(url) + `">` + htmltemplate.HTMLEscapeString(text) + "</a>")
+		}
+	})
+
+	return htmltemplate.HTML(out.String())
+}
+
+func highlight(code string) htmltemplate.HTML {
+	var out bytes.Buffer
+	iterator, err := chroma.Coalesce(lexers.Go).Tokenise(nil, code)
+	if err == nil {
+		err = highlighter.Format(&out, styles.Get("github"), iterator)
+	}
+	if err != nil {
+		return htmltemplate.HTML(`<pre class="chroma">` + htmltemplate.HTMLEscapeString(code) + "</pre>")
+	}
+
+	return htmltemplate.HTML(out.String())
+}
+
+// highlightCSS uses the light style by default and the dark style, if the page or the system prefers a dark theme.
+func highlightCSS() htmltemplate.CSS {
+	var css strings.Builder
+	write := func(style, scope string) {
+		var rules bytes.Buffer
+		if err := highlighter.WriteCSS(&rules, styles.Get(style)); err != nil {
+			return
+		}
+		for _, rule := range strings.Split(strings.TrimSpace(rules.String()), "\n") {
+			css.WriteString(scope + " " + rule + "\n")
+		}
+	}
+
+	write("github", ":root")
+	write("github-dark", ":root[data-theme=dark]")
+	css.WriteString("@media (prefers-color-scheme: dark) {\n")
+	write("github-dark", ":root:not([data-theme=light])")
+	css.WriteString("}\n")
+
+	return htmltemplate.CSS(css.String())
+}
@@ -0,0 +1,28 @@
+package internal
+
+import "testing"
+
+func TestDoc(t *testing.T) {
+	links := testDocLinks(t)
+	formatted := links.HTMLDoc("Run runs the [Server].", 4)
+
+	tests := []struct {
+		name  string
+		links *DocLinks
+		text  string
+		want  string
+	}{
+		{"formatted doc", links, formatted, formatted},
+		{"raw text", links, `<script>alert("x")</script>`, "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;"},
+		{"struct tag", links, `json:"name,omitempty"`, "json:&#34;name,omitempty&#34;"},
+		{"nil links", nil, "<p>text</p>", "&lt;p&gt;text&lt;/p&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.links.doc(tt.text)); got != tt.want {
+				t.Errorf("doc(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// PackageDir is a package, which matches a pattern like "./...".
+type PackageDir struct {
+	ImportPath string
+	Dir        string
+	// Path is the directory of the package relative to the root of the pattern, like "internal/foo".
+	Path string
+}
+
+// Index lists the packages, which were documented together.
+type Index struct {
+	Packages []IndexEntry
+}
+
+// IndexEntry is a package of an Index. Link is the path of its docs relative to the index.
+type IndexEntry struct {
+	Name       string
+	ImportPath string
+	Synopsis   string
+	Link       string
+}
+
+// ListPackages returns the packages matching pattern, which contain Go files in the build context of opts.
+// The paths of the packages are relative to the directory in front of "/...", or to their common parent directory,
+// if the pattern is not a directory.
+func ListPackages(pattern string, opts LoadOptions) ([]PackageDir, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles,
+		Env:  opts.env(),
+	}
+	if len(opts.Tags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(opts.Tags, ",")}
+	}
+
+	pkgs, err := packages.Load(cfg, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("error while listing packages %q: %w", pattern, err)
+	}
+
+	var dirs []PackageDir
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("error while listing package %q: %w", pkg.PkgPath, pkg.Errors[0])
+		}
+		if len(pkg.GoFiles) == 0 {
+			continue
+		}
+		dirs = append(dirs, PackageDir{ImportPath: pkg.PkgPath, Dir: filepath.Dir(pkg.GoFiles[0])})
+	}
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("no packages found for %q", pattern)
+	}
+
+	root := strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/")
+	if root == "" {
+		root = "."
+	}
+	if info, err := os.Stat(root); err == nil && info.IsDir() {
+		root, err = filepath.Abs(root)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		root = dirs[0].Dir
+		for _, dir := range dirs[1:] {
+			for !isParentDir(root, dir.Dir) {
+				root = filepath.Dir(root)
+			}
+		}
+	}
+
+	for i := range dirs {
+		rel, err := filepath.Rel(root, dirs[i].Dir)
+		if err != nil {
+			return nil, fmt.Errorf("error while resolving the path of %q: %w", dirs[i].ImportPath, err)
+		}
+		dirs[i].Path = filepath.ToSlash(rel)
+	}
+
+	return dirs, nil
+}
+
+func isParentDir(parent, dir string) bool {
+	rel, err := filepath.Rel(parent, dir)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
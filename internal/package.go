@@ -1,10 +1,26 @@
 package internal
 
-import "strings"
+import (
+	"go/token"
+	"strings"
+)
 
 type Package struct {
 	Name string
 	Doc  string
+	// Synopsis is the first sentence of Doc.
+	Synopsis string
+	// Dir is the directory of the source files. It is only known, if the package was parsed from source.
+	Dir string
+
+	ImportPath string
+	// ModulePath, ModuleVersion and GoVersion describe the module of the package. They are empty for packages of
+	// the standard library. ModuleVersion is only known for dependencies and tagged repositories.
+	ModulePath    string
+	ModuleVersion string
+	GoVersion     string
+	// moduleDir is the root directory of the module.
+	moduleDir string
 
 	Variables      []Variable
 	VariableBlocks []VariableBlock
@@ -16,24 +32,79 @@ type Package struct {
 	Types      []Type
 	Structs    []Struct
 	Interfaces []Interface
+
+	Examples []Example
+
+	// Platforms are the platforms, the package was loaded for. It is empty, if only the default build context was used.
+	Platforms []string
+
+	// Command is the command line interface of a main package. It is nil for other packages.
+	Command *Command
+
+	// Notes maps markers, like "BUG" or "TODO", to the notes of the package.
+	Notes map[string][]Note
+
+	// TOC is the table of contents of the package. It is only set, if it was requested with SetTOC.
+	TOC []TOCEntry
+
+	// imports maps the package names, under which the files import other packages, to their import paths.
+	imports map[string]string
 }
 
 type Function struct {
+	Name         string
+	Exported     bool
+	Availability Availability
+	Doc          string
+	Definition   string
+	Source       Source
+	Receiver     *Receiver
+	TypeParams   []TypeParam
+	Params       []Param
+	Results      []Param
+	TypeRefs     []TypeRef
+	Examples     []Example
+}
+
+// IsMethod reports whether the function has a receiver.
+func (i Function) IsMethod() bool {
+	return i.Receiver != nil
+}
+
+// IsVariadic reports whether the last parameter of the function is variadic.
+func (i Function) IsVariadic() bool {
+	return len(i.Params) > 0 && i.Params[len(i.Params)-1].IsVariadic
+}
+
+// Receiver is the receiver of a method. Type is the receiver type without the pointer.
+type Receiver struct {
+	Name      string
+	Type      string
+	IsPointer bool
+}
+
+// TypeParam is a type parameter of a generic function or type.
+type TypeParam struct {
 	Name       string
-	Doc        string
-	Definition string
+	Constraint string
 }
 
 func (i *Function) addToDocs(docs string) {
 	i.Doc += strings.TrimLeft(docs, " ") + "\n"
 }
 
+// Variable is a variable or a constant. The Value of a constant is its evaluated value,
+// the Value of a variable is the expression it is initialized with.
 type Variable struct {
-	Name       string
-	Doc        string
-	Definition string
-	Value      string
-	Type       string
+	Name         string
+	Exported     bool
+	Availability Availability
+	Doc          string
+	Definition   string
+	Source       Source
+	Value        string
+	Type         string
+	TypeRefs     []TypeRef
 }
 
 func (i *Variable) addToDocs(docs string) {
@@ -43,45 +114,321 @@ func (i *Variable) addToDocs(docs string) {
 type VariableBlock struct {
 	Variables []Variable
 	Doc       string
+	// Definition holds the specs of the block, one per line, without the surrounding "const (" and ")".
+	Definition string
 }
 
 func (i *VariableBlock) addToDocs(docs string) {
 	i.Doc += strings.TrimLeft(docs, " ") + "\n"
 }
 
+// Associated holds the declarations, which are listed under a type instead of the package:
+// constructors returning the type, and constants and variables of the type.
+type Associated struct {
+	Constructors   []Function
+	Constants      []Variable
+	ConstantBlocks []VariableBlock
+	Variables      []Variable
+	VariableBlocks []VariableBlock
+}
+
 type Type struct {
-	Doc        string
-	Name       string
-	Definition string
-	Functions  []Function
+	Doc          string
+	Name         string
+	Exported     bool
+	Availability Availability
+	Definition   string
+	Source       Source
+	TypeParams   []TypeParam
+	Enum         *Enum
+	Associated
+	Functions []Function
+	Examples  []Example
 }
 
 func (i *Type) addToDocs(docs string) {
 	i.Doc += strings.TrimLeft(docs, " ") + "\n"
 }
 
+// Enum holds the values of an integer type, which are declared in constant blocks using iota.
+type Enum struct {
+	Doc    string
+	Values []EnumValue
+}
+
+// HasStrings reports whether the String() output of any value is known.
+func (i Enum) HasStrings() bool {
+	for _, v := range i.Values {
+		if v.HasString {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EnumValue is a single value of an Enum. String is the output of the String method of the type,
+// if it could be evaluated statically.
+type EnumValue struct {
+	Name         string
+	Exported     bool
+	Availability Availability
+	Value        string
+	String       string
+	HasString    bool
+	Doc          string
+}
+
 type Struct struct {
-	Doc        string
-	Name       string
-	Definition string
-	Functions  []Function
+	Doc          string
+	Name         string
+	Exported     bool
+	Availability Availability
+	Definition   string
+	Source       Source
+	TypeParams   []TypeParam
+	Associated
+	Fields    []Field
+	Functions []Function
+	Examples  []Example
 }
 
 func (i *Struct) addToDocs(docs string) {
 	i.Doc += strings.TrimLeft(docs, " ") + "\n"
 }
 
-type Interface struct {
-	Doc        string
+// HasFieldDocs reports whether any field of the struct has a doc comment, a line comment or a struct tag,
+// or is not available on all platforms.
+func (i Struct) HasFieldDocs() bool {
+	for _, f := range i.Fields {
+		if f.Doc != "" || f.Comment != "" || f.Tag != "" || len(f.Availability) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TagKeys returns the keys of all struct tags used by the fields, in order of their first appearance.
+func (i Struct) TagKeys() (keys []string) {
+	seen := make(map[string]bool)
+	for _, f := range i.Fields {
+		for _, tag := range parseStructTag(f.Tag) {
+			if !seen[tag.Key] {
+				seen[tag.Key] = true
+				keys = append(keys, tag.Key)
+			}
+		}
+	}
+
+	return
+}
+
+type Field struct {
+	Name         string
+	Exported     bool
+	Availability Availability
+	Type         string
+	Source       Source
+	Doc          string
+	Comment      string
+	Tag          string
+	Tags         map[string]string
+	IsEmbedded   bool
+	TypeRefs     []TypeRef
+}
+
+// Description returns the doc comment of the field, or its line comment if it has no doc comment.
+func (i Field) Description() string {
+	if i.Doc != "" {
+		return i.Doc
+	}
+
+	return i.Comment
+}
+
+// TypeRef references a named type by the import path of its package and its name.
+type TypeRef struct {
+	PkgPath string
+	PkgName string
+	Name    string
+}
+
+func (i TypeRef) String() string {
+	return i.PkgName + "." + i.Name
+}
+
+// Method is a method of an interface.
+type Method struct {
+	Name         string
+	Exported     bool
+	Availability Availability
+	Doc          string
+	Definition   string
+	Source       Source
+	Params       []Param
+	Results      []Param
+	TypeRefs     []TypeRef
+}
+
+// Param is a parameter or a result of a function or method.
+// The type of a variadic parameter is printed with its leading "...".
+type Param struct {
 	Name       string
-	Definition string
-	Values     []Variable
+	Type       string
+	IsVariadic bool
+}
+
+// Interface is an interface type. Constraint interfaces, which can only be used as type constraints,
+// list their type set elements, like ~int | ~string, in Unions.
+type Interface struct {
+	Doc          string
+	Name         string
+	Exported     bool
+	Availability Availability
+	Definition   string
+	Source       Source
+	TypeParams   []TypeParam
+	Methods      []Method
+	Embedded     []Field
+	Unions       []Union
+	IsConstraint bool
+	Associated
+	Examples []Example
 }
 
 func (i *Interface) addToDocs(docs string) {
 	i.Doc += strings.TrimLeft(docs, " ") + "\n"
 }
 
+// Example is an Example function from a _test.go file.
+type Example struct {
+	Name        string
+	Suffix      string
+	Doc         string
+	Code        string
+	Output      string
+	HasOutput   bool
+	IsUnordered bool
+}
+
+// Note is a marked comment, like "BUG(who): text", which is not attached to a declaration.
+type Note struct {
+	Author string
+	Body   string
+	File   string
+	Line   int
+}
+
+// FilterNotes removes all notes, whose marker is not one of markers.
+func (p *Package) FilterNotes(markers []string) {
+	for marker := range p.Notes {
+		keep := false
+		for _, m := range markers {
+			if m == marker {
+				keep = true
+			}
+		}
+		if !keep {
+			delete(p.Notes, marker)
+		}
+	}
+}
+
+// markExported sets the Exported flag of all symbols of the package.
+func (p *Package) markExported() {
+	markVariables := func(variables []Variable) {
+		for i := range variables {
+			variables[i].Exported = token.IsExported(variables[i].Name)
+		}
+	}
+	markBlocks := func(blocks []VariableBlock) {
+		for _, b := range blocks {
+			markVariables(b.Variables)
+		}
+	}
+	markFunctions := func(functions []Function) {
+		for i := range functions {
+			functions[i].Exported = token.IsExported(functions[i].Name)
+		}
+	}
+	markAssociated := func(a *Associated) {
+		markFunctions(a.Constructors)
+		markVariables(a.Constants)
+		markBlocks(a.ConstantBlocks)
+		markVariables(a.Variables)
+		markBlocks(a.VariableBlocks)
+	}
+
+	markVariables(p.Constants)
+	markBlocks(p.ConstantBlocks)
+	markVariables(p.Variables)
+	markBlocks(p.VariableBlocks)
+	markFunctions(p.Functions)
+
+	for i := range p.Types {
+		t := &p.Types[i]
+		t.Exported = token.IsExported(t.Name)
+		if t.Enum != nil {
+			for j := range t.Enum.Values {
+				t.Enum.Values[j].Exported = token.IsExported(t.Enum.Values[j].Name)
+			}
+		}
+		markAssociated(&t.Associated)
+		markFunctions(t.Functions)
+	}
+
+	for i := range p.Structs {
+		s := &p.Structs[i]
+		s.Exported = token.IsExported(s.Name)
+		for j := range s.Fields {
+			s.Fields[j].Exported = token.IsExported(s.Fields[j].Name)
+		}
+		markAssociated(&s.Associated)
+		markFunctions(s.Functions)
+	}
+
+	for i := range p.Interfaces {
+		in := &p.Interfaces[i]
+		in.Exported = token.IsExported(in.Name)
+		for j := range in.Methods {
+			in.Methods[j].Exported = token.IsExported(in.Methods[j].Name)
+		}
+		for j := range in.Embedded {
+			in.Embedded[j].Exported = token.IsExported(in.Embedded[j].Name)
+		}
+		markAssociated(&in.Associated)
+	}
+}
+
+// Union is a type set element of an interface, which consists of one or more terms separated by "|".
+type Union struct {
+	Terms []Term
+}
+
+func (i Union) String() string {
+	var terms []string
+	for _, t := range i.Terms {
+		terms = append(terms, t.String())
+	}
+
+	return strings.Join(terms, " | ")
+}
+
+// Term is a single type in a Union. IsTilde is set for terms like ~int, which include all types with that underlying type.
+type Term struct {
+	Type    string
+	IsTilde bool
+}
+
+func (i Term) String() string {
+	if i.IsTilde {
+		return "~" + i.Type
+	}
+
+	return i.Type
+}
+
 type documentable interface {
 	addToDocs(string)
 }
@@ -142,6 +489,18 @@ func (p Package) getLastStruct() *Struct {
 	return &Struct{}
 }
 
+// getLastAssociated returns the associated declarations of the last struct, interface or other type.
+func (p Package) getLastAssociated(kind string) *Associated {
+	switch kind {
+	case "struct":
+		return &p.getLastStruct().Associated
+	case "interface":
+		return &p.getLastInterface().Associated
+	default:
+		return &p.getLastType().Associated
+	}
+}
+
 func (p Package) getLastInterface() *Interface {
 	if len(p.Interfaces) > 0 {
 		return &p.Interfaces[len(p.Interfaces)-1]
@@ -0,0 +1,500 @@
+package internal
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Availability lists the platforms, on which a symbol is available.
+// It is empty, if the symbol is available on all platforms.
+type Availability []string
+
+func (i Availability) String() string {
+	return strings.Join(i, ", ")
+}
+
+// BadgeURL returns the URL of a shields.io badge, which lists the platforms.
+func (i Availability) BadgeURL() string {
+	escape := func(s string) string {
+		s = strings.ReplaceAll(s, "-", "--")
+		s = strings.ReplaceAll(s, "_", "__")
+		return url.PathEscape(s)
+	}
+
+	return "https://img.shields.io/badge/" + escape("available on") + "-" + escape(strings.Join(i, " | ")) + "-blue"
+}
+
+// AvailabilityRow is a row of the availability matrix of a package.
+type AvailabilityRow struct {
+	Name      string
+	Available []bool
+}
+
+// AvailabilityMatrix returns a row for each symbol, which is not available on all platforms of the package.
+func (p Package) AvailabilityMatrix() (rows []AvailabilityRow) {
+	addRow := func(name string, availability Availability) {
+		if len(availability) == 0 {
+			return
+		}
+		row := AvailabilityRow{Name: name}
+		for _, platform := range p.Platforms {
+			available := false
+			for _, a := range availability {
+				if a == platform {
+					available = true
+				}
+			}
+			row.Available = append(row.Available, available)
+		}
+		rows = append(rows, row)
+	}
+	addVariables := func(variables []Variable, blocks []VariableBlock) {
+		for _, v := range variables {
+			addRow(v.Name, v.Availability)
+		}
+		for _, b := range blocks {
+			for _, v := range b.Variables {
+				addRow(v.Name, v.Availability)
+			}
+		}
+	}
+	addFunctions := func(functions []Function, prefix string) {
+		for _, f := range functions {
+			addRow(prefix+f.Name, f.Availability)
+		}
+	}
+	addAssociated := func(a Associated) {
+		addFunctions(a.Constructors, "")
+		addVariables(a.Constants, a.ConstantBlocks)
+		addVariables(a.Variables, a.VariableBlocks)
+	}
+
+	addVariables(p.Constants, p.ConstantBlocks)
+	addVariables(p.Variables, p.VariableBlocks)
+	addFunctions(p.Functions, "")
+	for _, t := range p.Types {
+		addRow(t.Name, t.Availability)
+		if t.Enum != nil {
+			for _, v := range t.Enum.Values {
+				addRow(v.Name, v.Availability)
+			}
+		}
+		addAssociated(t.Associated)
+		addFunctions(t.Functions, t.Name+".")
+	}
+	for _, s := range p.Structs {
+		addRow(s.Name, s.Availability)
+		for _, f := range s.Fields {
+			addRow(s.Name+"."+f.Name, f.Availability)
+		}
+		addAssociated(s.Associated)
+		addFunctions(s.Functions, s.Name+".")
+	}
+	for _, in := range p.Interfaces {
+		addRow(in.Name, in.Availability)
+		addAssociated(in.Associated)
+		for _, m := range in.Methods {
+			addRow(in.Name+"."+m.Name, m.Availability)
+		}
+	}
+
+	return
+}
+
+// LoadPlatforms loads a package with load once for every platform and merges the results.
+// Each symbol is annotated with the platforms, on which it is available.
+//
+// A platform has the form "goos/goarch:tag+tag", where goarch and the tags are optional, like "linux", "windows/arm64"
+// or "linux/amd64:netgo".
+// It replaces the GOOS and, if set, the GOARCH of opts. The tags are added to the tags of opts.
+func LoadPlatforms(platforms []string, opts LoadOptions, load func(LoadOptions) (Package, error)) (Package, error) {
+	var merged Package
+	var symbols []map[string]string
+	for i, platform := range platforms {
+		platformOpts, err := parsePlatform(platform, opts)
+		if err != nil {
+			return Package{}, err
+		}
+		pkg, err := load(platformOpts)
+		if err != nil {
+			return Package{}, fmt.Errorf("error while loading package for platform %q: %w", platform, err)
+		}
+
+		symbols = append(symbols, pkg.symbolAnchors())
+		if i == 0 {
+			merged = pkg
+		} else {
+			merged.merge(pkg)
+		}
+	}
+
+	merged.Platforms = platforms
+	merged.setAvailability(symbols)
+
+	return merged, nil
+}
+
+func parsePlatform(platform string, opts LoadOptions) (LoadOptions, error) {
+	target, tags, hasTags := strings.Cut(platform, ":")
+	goos, goarch, _ := strings.Cut(target, "/")
+	if goos == "" {
+		return opts, fmt.Errorf("invalid platform %q, must have the form goos[/goarch][:tag+tag...]", platform)
+	}
+
+	opts.GOOS = goos
+	if goarch != "" {
+		opts.GOARCH = goarch
+	}
+	if hasTags {
+		opts.Tags = append(append([]string{}, opts.Tags...), strings.Split(tags, "+")...)
+	}
+
+	return opts, nil
+}
+
+// merge adds the symbols of other, which are missing in the package, and sorts the symbols by name again.
+// Struct fields, interface methods and enum values are kept in the order of their declaration.
+func (p *Package) merge(other Package) {
+	symbols := p.symbolAnchors()
+
+	mergeVariables(&p.Constants, other.Constants, symbols)
+	mergeBlocks(&p.ConstantBlocks, other.ConstantBlocks, symbols)
+	mergeVariables(&p.Variables, other.Variables, symbols)
+	mergeBlocks(&p.VariableBlocks, other.VariableBlocks, symbols)
+	mergeFunctions(&p.Functions, other.Functions, "", symbols)
+
+	for _, t := range other.Types {
+		if i := findType(p.Types, t.Name); i >= 0 {
+			p.Types[i].Associated.merge(t.Associated, symbols)
+			mergeFunctions(&p.Types[i].Functions, t.Functions, t.Name+".", symbols)
+			if t.Enum != nil {
+				if p.Types[i].Enum == nil {
+					p.Types[i].Enum = &Enum{Doc: t.Enum.Doc}
+				}
+				mergeEnumValues(&p.Types[i].Enum.Values, t.Enum.Values, symbols)
+			}
+		} else if _, ok := symbols[t.Name]; !ok {
+			p.Types = append(p.Types, t)
+		}
+	}
+	for _, s := range other.Structs {
+		if i := findStruct(p.Structs, s.Name); i >= 0 {
+			p.Structs[i].Associated.merge(s.Associated, symbols)
+			mergeFunctions(&p.Structs[i].Functions, s.Functions, s.Name+".", symbols)
+			mergeFields(&p.Structs[i].Fields, s.Fields, s.Name+".", symbols)
+		} else if _, ok := symbols[s.Name]; !ok {
+			p.Structs = append(p.Structs, s)
+		}
+	}
+	for _, in := range other.Interfaces {
+		if i := findInterface(p.Interfaces, in.Name); i >= 0 {
+			p.Interfaces[i].Associated.merge(in.Associated, symbols)
+			mergeMethods(&p.Interfaces[i].Methods, in.Methods, in.Name+".", symbols)
+		} else if _, ok := symbols[in.Name]; !ok {
+			p.Interfaces = append(p.Interfaces, in)
+		}
+	}
+
+	for marker, notes := range other.Notes {
+		for _, note := range notes {
+			if !containsNote(p.Notes[marker], note) {
+				if p.Notes == nil {
+					p.Notes = make(map[string][]Note)
+				}
+				p.Notes[marker] = append(p.Notes[marker], note)
+			}
+		}
+	}
+	for name, importPath := range other.imports {
+		if _, ok := p.imports[name]; !ok {
+			if p.imports == nil {
+				p.imports = make(map[string]string)
+			}
+			p.imports[name] = importPath
+		}
+	}
+
+	p.sortByName()
+}
+
+// sortByName sorts the symbols of the package by name, like go/doc does. Blocks are sorted by their first value.
+func (p *Package) sortByName() {
+	sortVariables(p.Constants)
+	sortBlocks(p.ConstantBlocks)
+	sortVariables(p.Variables)
+	sortBlocks(p.VariableBlocks)
+	sortFunctions(p.Functions)
+
+	sort.SliceStable(p.Types, func(a, b int) bool { return p.Types[a].Name < p.Types[b].Name })
+	for i := range p.Types {
+		p.Types[i].Associated.sortByName()
+		sortFunctions(p.Types[i].Functions)
+	}
+	sort.SliceStable(p.Structs, func(a, b int) bool { return p.Structs[a].Name < p.Structs[b].Name })
+	for i := range p.Structs {
+		p.Structs[i].Associated.sortByName()
+		sortFunctions(p.Structs[i].Functions)
+	}
+	sort.SliceStable(p.Interfaces, func(a, b int) bool { return p.Interfaces[a].Name < p.Interfaces[b].Name })
+	for i := range p.Interfaces {
+		p.Interfaces[i].Associated.sortByName()
+	}
+}
+
+func (i *Associated) sortByName() {
+	sortFunctions(i.Constructors)
+	sortVariables(i.Constants)
+	sortBlocks(i.ConstantBlocks)
+	sortVariables(i.Variables)
+	sortBlocks(i.VariableBlocks)
+}
+
+func sortVariables(variables []Variable) {
+	sort.SliceStable(variables, func(a, b int) bool { return variables[a].Name < variables[b].Name })
+}
+
+func sortBlocks(blocks []VariableBlock) {
+	first := func(b VariableBlock) string {
+		if len(b.Variables) == 0 {
+			return ""
+		}
+		return b.Variables[0].Name
+	}
+	sort.SliceStable(blocks, func(a, b int) bool { return first(blocks[a]) < first(blocks[b]) })
+}
+
+func sortFunctions(functions []Function) {
+	sort.SliceStable(functions, func(a, b int) bool { return functions[a].Name < functions[b].Name })
+}
+
+func (i *Associated) merge(other Associated, symbols map[string]string) {
+	mergeFunctions(&i.Constructors, other.Constructors, "", symbols)
+	mergeVariables(&i.Constants, other.Constants, symbols)
+	mergeBlocks(&i.ConstantBlocks, other.ConstantBlocks, symbols)
+	mergeVariables(&i.Variables, other.Variables, symbols)
+	mergeBlocks(&i.VariableBlocks, other.VariableBlocks, symbols)
+}
+
+func mergeVariables(variables *[]Variable, other []Variable, symbols map[string]string) {
+	for _, v := range other {
+		if _, ok := symbols[v.Name]; !ok {
+			*variables = append(*variables, v)
+		}
+	}
+}
+
+// mergeBlocks adds the blocks of other, which only contain missing variables.
+// Blocks, which exist with different variables on the platforms, are kept as they are.
+func mergeBlocks(blocks *[]VariableBlock, other []VariableBlock, symbols map[string]string) {
+	for _, b := range other {
+		missing := true
+		for _, v := range b.Variables {
+			if _, ok := symbols[v.Name]; ok {
+				missing = false
+			}
+		}
+		if missing {
+			*blocks = append(*blocks, b)
+		}
+	}
+}
+
+// mergeFunctions adds the functions of other, which are missing. prefix is "Type." for methods.
+func mergeFunctions(functions *[]Function, other []Function, prefix string, symbols map[string]string) {
+	for _, f := range other {
+		if _, ok := symbols[prefix+f.Name]; !ok {
+			*functions = append(*functions, f)
+		}
+	}
+}
+
+// mergeFields inserts the fields of other, which are missing, after the field, which precedes them in other.
+func mergeFields(fields *[]Field, other []Field, prefix string, symbols map[string]string) {
+	at := 0
+	for _, f := range other {
+		if f.Name == "_" {
+			continue
+		}
+		if _, ok := symbols[prefix+f.Name]; ok {
+			at = findField(*fields, f.Name) + 1
+			continue
+		}
+		*fields = slices.Insert(*fields, at, f)
+		at++
+	}
+}
+
+// mergeMethods inserts the interface methods of other, which are missing, like mergeFields.
+func mergeMethods(methods *[]Method, other []Method, prefix string, symbols map[string]string) {
+	at := 0
+	for _, m := range other {
+		if _, ok := symbols[prefix+m.Name]; ok {
+			at = findMethod(*methods, m.Name) + 1
+			continue
+		}
+		*methods = slices.Insert(*methods, at, m)
+		at++
+	}
+}
+
+// mergeEnumValues inserts the enum values of other, which are missing, like mergeFields.
+func mergeEnumValues(values *[]EnumValue, other []EnumValue, symbols map[string]string) {
+	at := 0
+	for _, v := range other {
+		if _, ok := symbols[v.Name]; ok {
+			at = findEnumValue(*values, v.Name) + 1
+			continue
+		}
+		*values = slices.Insert(*values, at, v)
+		at++
+	}
+}
+
+func findField(fields []Field, name string) int {
+	for i, f := range fields {
+		if f.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func findMethod(methods []Method, name string) int {
+	for i, m := range methods {
+		if m.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func findEnumValue(values []EnumValue, name string) int {
+	for i, v := range values {
+		if v.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func findType(types []Type, name string) int {
+	for i, t := range types {
+		if t.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func findStruct(structs []Struct, name string) int {
+	for i, s := range structs {
+		if s.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func findInterface(interfaces []Interface, name string) int {
+	for i, in := range interfaces {
+		if in.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func containsNote(notes []Note, note Note) bool {
+	for _, n := range notes {
+		if n == note {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setAvailability sets the availability of all symbols. symbols holds the symbols of each platform.
+func (p *Package) setAvailability(symbols []map[string]string) {
+	availability := func(name string) Availability {
+		var result Availability
+		for i, platformSymbols := range symbols {
+			if _, ok := platformSymbols[name]; ok {
+				result = append(result, p.Platforms[i])
+			}
+		}
+		if len(result) == len(p.Platforms) {
+			return nil
+		}
+
+		return result
+	}
+	setVariables := func(variables []Variable) {
+		for i := range variables {
+			variables[i].Availability = availability(variables[i].Name)
+		}
+	}
+	setBlocks := func(blocks []VariableBlock) {
+		for _, b := range blocks {
+			setVariables(b.Variables)
+		}
+	}
+	setFunctions := func(functions []Function, prefix string) {
+		for i := range functions {
+			functions[i].Availability = availability(prefix + functions[i].Name)
+		}
+	}
+	setAssociated := func(a *Associated) {
+		setFunctions(a.Constructors, "")
+		setVariables(a.Constants)
+		setBlocks(a.ConstantBlocks)
+		setVariables(a.Variables)
+		setBlocks(a.VariableBlocks)
+	}
+
+	setVariables(p.Constants)
+	setBlocks(p.ConstantBlocks)
+	setVariables(p.Variables)
+	setBlocks(p.VariableBlocks)
+	setFunctions(p.Functions, "")
+
+	for i := range p.Types {
+		t := &p.Types[i]
+		t.Availability = availability(t.Name)
+		if t.Enum != nil {
+			for j := range t.Enum.Values {
+				t.Enum.Values[j].Availability = availability(t.Enum.Values[j].Name)
+			}
+		}
+		setAssociated(&t.Associated)
+		setFunctions(t.Functions, t.Name+".")
+	}
+	for i := range p.Structs {
+		s := &p.Structs[i]
+		s.Availability = availability(s.Name)
+		for j := range s.Fields {
+			if s.Fields[j].Name != "_" {
+				s.Fields[j].Availability = availability(s.Name + "." + s.Fields[j].Name)
+			}
+		}
+		setAssociated(&s.Associated)
+		setFunctions(s.Functions, s.Name+".")
+	}
+	for i := range p.Interfaces {
+		in := &p.Interfaces[i]
+		in.Availability = availability(in.Name)
+		setAssociated(&in.Associated)
+		for j := range in.Methods {
+			in.Methods[j].Availability = availability(in.Name + "." + in.Methods[j].Name)
+		}
+	}
+}
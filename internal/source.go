@@ -0,0 +1,255 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"golang.org/x/mod/semver"
+)
+
+// Source is the position of a declaration. URL links to the source, if a source URL is known.
+type Source struct {
+	File    string
+	Line    int
+	EndLine int
+	URL     string
+}
+
+// SourceRepo is the git repository, which contains a package.
+type SourceRepo struct {
+	// Host is the host of the remote, like "github.com".
+	Host string
+	// Repo is the path of the repository on the host, like "MarvinJWendt/gomark".
+	Repo string
+	// Ref is the commit, the docs are generated for.
+	Ref string
+	// Root is the local directory of the repository.
+	Root string
+	// Tags are the tags, which point to Ref.
+	Tags []string
+}
+
+// SourceURLData is passed to the source URL template.
+type SourceURLData struct {
+	Host    string
+	Repo    string
+	Ref     string
+	File    string
+	Line    int
+	EndLine int
+}
+
+// DetectSourceRepo reads the "origin" remote, the HEAD commit and its tags of the git repository, which contains dir.
+// Without an "origin" remote, the repository is returned without its host together with the error.
+func DetectSourceRepo(dir string) (SourceRepo, error) {
+	if dir == "" {
+		return SourceRepo{}, fmt.Errorf("the directory of the package is unknown")
+	}
+
+	git := func(args ...string) (string, error) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("error while running \"git %s\": %w", strings.Join(args, " "), err)
+		}
+		return strings.TrimSpace(string(output)), nil
+	}
+
+	var repo SourceRepo
+	var err error
+	if repo.Root, err = git("rev-parse", "--show-toplevel"); err != nil {
+		return SourceRepo{}, err
+	}
+	if repo.Ref, err = git("rev-parse", "HEAD"); err != nil {
+		return SourceRepo{}, err
+	}
+	if tags, _ := git("tag", "--points-at", "HEAD"); tags != "" {
+		repo.Tags = strings.Split(tags, "\n")
+	}
+	remote, err := git("remote", "get-url", "origin")
+	if err != nil {
+		return repo, err
+	}
+	repo.Host, repo.Repo = parseRemote(remote)
+
+	return repo, nil
+}
+
+// SetModuleVersion sets the module version from the tags of the repository, if it is not known yet.
+func (p *Package) SetModuleVersion(repo SourceRepo) {
+	if p.ModulePath == "" || p.ModuleVersion != "" || repo.Root == "" || p.moduleDir == "" {
+		return
+	}
+
+	// git reports the root with symlinks resolved.
+	moduleDir, err := filepath.EvalSymlinks(p.moduleDir)
+	if err != nil {
+		moduleDir = p.moduleDir
+	}
+	p.ModuleVersion = moduleVersion(repo.Tags, repo.Root, moduleDir)
+}
+
+// moduleVersion returns the highest semantic version among tags, which names the module in moduleDir.
+// Like the go command expects it, a module in a subdirectory of the repository is tagged with the subdirectory as
+// prefix, like "sub/v1.2.3", and a module at the root of the repository is tagged without a prefix.
+func moduleVersion(tags []string, root, moduleDir string) string {
+	subdir, err := filepath.Rel(root, moduleDir)
+	if err != nil || strings.HasPrefix(subdir, "..") {
+		return ""
+	}
+	prefix := ""
+	if subdir != "." {
+		prefix = filepath.ToSlash(subdir) + "/"
+	}
+
+	var version string
+	for _, tag := range tags {
+		v, ok := strings.CutPrefix(tag, prefix)
+		if ok && semver.Canonical(v) == v && semver.Compare(v, version) > 0 {
+			version = v
+		}
+	}
+
+	return version
+}
+
+// parseRemote splits a git remote URL, like "git@github.com:user/repo.git" or "https://github.com/user/repo",
+// into its host and the path of the repository.
+func parseRemote(remote string) (host, repo string) {
+	remote = strings.TrimSuffix(remote, ".git")
+	if i := strings.Index(remote, "://"); i >= 0 {
+		remote = remote[i+3:]
+	} else {
+		// scp-like syntax: [user@]host:path
+		remote = strings.Replace(remote, ":", "/", 1)
+	}
+	if i := strings.Index(remote, "@"); i >= 0 && i < strings.Index(remote, "/") {
+		remote = remote[i+1:]
+	}
+
+	host, repo, _ = strings.Cut(remote, "/")
+	return host, repo
+}
+
+// DefaultSourceURL returns the source URL template for a repository host.
+func DefaultSourceURL(host string) string {
+	switch {
+	case strings.Contains(host, "gitlab"):
+		return "https://{{.Host}}/{{.Repo}}/-/blob/{{.Ref}}/{{.File}}#L{{.Line}}-{{.EndLine}}"
+	case strings.Contains(host, "bitbucket"):
+		return "https://{{.Host}}/{{.Repo}}/src/{{.Ref}}/{{.File}}#lines-{{.Line}}:{{.EndLine}}"
+	default:
+		return "https://{{.Host}}/{{.Repo}}/blob/{{.Ref}}/{{.File}}#L{{.Line}}-L{{.EndLine}}"
+	}
+}
+
+// LinkSources makes the source files of all symbols relative to the repository root and sets their URLs.
+// If urlTemplate is empty, the template is chosen by the host of the repository.
+// Without a repository, the files are made relative to the directory of the package.
+func (p *Package) LinkSources(urlTemplate string, repo SourceRepo) error {
+	if urlTemplate == "" && repo.Host != "" {
+		urlTemplate = DefaultSourceURL(repo.Host)
+	}
+	var t *template.Template
+	if urlTemplate != "" {
+		var err error
+		t, err = template.New("source-url").Parse(urlTemplate)
+		if err != nil {
+			return fmt.Errorf("error while parsing source URL: %w", err)
+		}
+	}
+
+	root := repo.Root
+	if root == "" {
+		root = p.Dir
+	}
+
+	var err error
+	p.forEachSource(func(source *Source) {
+		if source.File == "" || err != nil {
+			return
+		}
+		if rel, relErr := filepath.Rel(root, source.File); relErr == nil {
+			source.File = filepath.ToSlash(rel)
+		}
+		if t == nil {
+			return
+		}
+
+		var url bytes.Buffer
+		err = t.Execute(&url, SourceURLData{
+			Host:    repo.Host,
+			Repo:    repo.Repo,
+			Ref:     repo.Ref,
+			File:    source.File,
+			Line:    source.Line,
+			EndLine: source.EndLine,
+		})
+		source.URL = url.String()
+	})
+	if err != nil {
+		return fmt.Errorf("error while executing source URL: %w", err)
+	}
+
+	return nil
+}
+
+// forEachSource calls fn with the source of every symbol of the package.
+func (p *Package) forEachSource(fn func(source *Source)) {
+	variables := func(variables []Variable) {
+		for i := range variables {
+			fn(&variables[i].Source)
+		}
+	}
+	blocks := func(blocks []VariableBlock) {
+		for _, b := range blocks {
+			variables(b.Variables)
+		}
+	}
+	functions := func(functions []Function) {
+		for i := range functions {
+			fn(&functions[i].Source)
+		}
+	}
+	associated := func(a *Associated) {
+		functions(a.Constructors)
+		variables(a.Constants)
+		blocks(a.ConstantBlocks)
+		variables(a.Variables)
+		blocks(a.VariableBlocks)
+	}
+
+	variables(p.Constants)
+	blocks(p.ConstantBlocks)
+	variables(p.Variables)
+	blocks(p.VariableBlocks)
+	functions(p.Functions)
+
+	for i := range p.Types {
+		fn(&p.Types[i].Source)
+		associated(&p.Types[i].Associated)
+		functions(p.Types[i].Functions)
+	}
+	for i := range p.Structs {
+		s := &p.Structs[i]
+		fn(&s.Source)
+		for j := range s.Fields {
+			fn(&s.Fields[j].Source)
+		}
+		associated(&s.Associated)
+		functions(s.Functions)
+	}
+	for i := range p.Interfaces {
+		in := &p.Interfaces[i]
+		fn(&in.Source)
+		for j := range in.Methods {
+			fn(&in.Methods[j].Source)
+		}
+		associated(&in.Associated)
+	}
+}
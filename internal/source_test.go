@@ -0,0 +1,29 @@
+package internal
+
+import "testing"
+
+func TestModuleVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		tags      []string
+		moduleDir string
+		want      string
+	}{
+		{"root module", []string{"v1.2.0"}, "/repo", "v1.2.0"},
+		{"highest version", []string{"v1.2.0", "v1.10.0", "v1.9.0"}, "/repo", "v1.10.0"},
+		{"root ignores nested tags", []string{"sub/v0.3.0"}, "/repo", ""},
+		{"nested module", []string{"v1.2.0", "sub/v0.3.0"}, "/repo/sub", "v0.3.0"},
+		{"nested module ignores root tags", []string{"v1.2.0"}, "/repo/sub", ""},
+		{"deeply nested module", []string{"sub/v0.3.0", "sub/deep/v0.1.0"}, "/repo/sub/deep", "v0.1.0"},
+		{"no semantic version", []string{"release", "v1"}, "/repo", ""},
+		{"outside of the repository", []string{"v1.2.0"}, "/other", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := moduleVersion(tt.tags, "/repo", tt.moduleDir); got != tt.want {
+				t.Errorf("moduleVersion(%q, %q) = %q, want %q", tt.tags, tt.moduleDir, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// TemplateNames returns the sorted names of the built-in templates.
+func TemplateNames() []string {
+	return templateNames(Templates)
+}
+
+func templateNames(templates map[string]string) []string {
+	var names []string
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ParseTemplate parses the template file or, if file is empty, the built-in template called name.
+// The template is named after the file it comes from, so parse and execution errors point to the file and line.
+func ParseTemplate(file, name string) (*template.Template, error) {
+	source, text, err := readTemplate(file, name, Templates, ".tmpl.md")
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := template.New(source).Funcs(sprig.TxtFuncMap()).Funcs(FuncMap(nil, 1)).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing template: %w", err)
+	}
+
+	return t, nil
+}
+
+// ParseHTMLTemplate parses the html/template file or, if file is empty, the built-in HTML template called name.
+func ParseHTMLTemplate(file, name string) (*htmltemplate.Template, error) {
+	source, text, err := readTemplate(file, name, HTMLTemplates, ".tmpl.html")
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := htmltemplate.New(source).Funcs(sprig.HtmlFuncMap()).Funcs(HTMLFuncMap(nil)).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing template: %w", err)
+	}
+
+	return t, nil
+}
+
+// readTemplate returns the name and the content of the template file or of the built-in template called name.
+func readTemplate(file, name string, templates map[string]string, extension string) (source, text string, err error) {
+	if file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", "", fmt.Errorf("error while reading template: %w", err)
+		}
+		return file, string(content), nil
+	}
+
+	text, ok := templates[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown template %q, must be one of: %s", name, strings.Join(templateNames(templates), ", "))
+	}
+
+	return name + extension, text, nil
+}
+
+// ParsePartials parses all files in dir, which end with ".tmpl" or ".tmpl.md", into t.
+// The blocks, which the files define with {{define "name"}}, replace the blocks of t with the same name,
+// like "header", "constants", "variables", "functions", "types", "structs" or "interfaces".
+func ParsePartials(t *template.Template, dir string) error {
+	return readPartials(dir, ".tmpl.md", func(file, text string) error {
+		_, err := t.New(file).Parse(text)
+		return err
+	})
+}
+
+// ParseHTMLPartials parses all files in dir, which end with ".tmpl" or ".tmpl.html", into t, like ParsePartials.
+func ParseHTMLPartials(t *htmltemplate.Template, dir string) error {
+	return readPartials(dir, ".tmpl.html", func(file, text string) error {
+		_, err := t.New(file).Parse(text)
+		return err
+	})
+}
+
+// readPartials calls parse with the path and the content of all files in dir, which end with ".tmpl" or extension.
+func readPartials(dir, extension string, parse func(file, text string) error) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("error while reading partials: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !(strings.HasSuffix(name, ".tmpl") || strings.HasSuffix(name, extension)) {
+			continue
+		}
+
+		file := filepath.Join(dir, name)
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("error while reading partial: %w", err)
+		}
+		err = parse(file, string(content))
+		if err != nil {
+			return fmt.Errorf("error while parsing partial: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package internal
+
+import "strings"
+
+// TOCEntry is an entry of the table of contents, which links the signature of a symbol to its anchor.
+type TOCEntry struct {
+	Signature string
+	Anchor    string
+	// Entries are the constructors and methods of a type.
+	Entries []TOCEntry
+}
+
+// SetTOC sets the table of contents of the package, which lists all constants, variables, functions, types and
+// methods in the order of the templates, like the index on pkg.go.dev.
+func (p *Package) SetTOC() {
+	anchors := p.symbolAnchors()
+	entry := func(name, signature string) TOCEntry {
+		return TOCEntry{Signature: signature, Anchor: anchors[name]}
+	}
+	values := func(keyword string, values []Variable, blocks []VariableBlock) (entries []TOCEntry) {
+		for _, v := range values {
+			entries = append(entries, entry(v.Name, keyword+" "+v.Name))
+		}
+		for _, b := range blocks {
+			for _, v := range b.Variables {
+				entries = append(entries, entry(v.Name, keyword+" "+v.Name))
+			}
+		}
+		return entries
+	}
+	functions := func(prefix string, functions []Function) (entries []TOCEntry) {
+		for _, f := range functions {
+			entries = append(entries, entry(prefix+f.Name, singleLine(f.Definition)))
+		}
+		return entries
+	}
+	typeEntry := func(name, definition string, a Associated, methods []Function) TOCEntry {
+		e := entry(name, typeSignature(definition))
+		e.Entries = append(functions("", a.Constructors), functions(name+".", methods)...)
+		return e
+	}
+
+	var toc []TOCEntry
+	toc = append(toc, values("const", p.Constants, p.ConstantBlocks)...)
+	toc = append(toc, values("var", p.Variables, p.VariableBlocks)...)
+	toc = append(toc, functions("", p.Functions)...)
+	for _, t := range p.Types {
+		toc = append(toc, typeEntry(t.Name, t.Definition, t.Associated, t.Functions))
+	}
+	for _, s := range p.Structs {
+		toc = append(toc, typeEntry(s.Name, s.Definition, s.Associated, s.Functions))
+	}
+	for _, in := range p.Interfaces {
+		e := typeEntry(in.Name, in.Definition, in.Associated, nil)
+		for _, m := range in.Methods {
+			e.Entries = append(e.Entries, entry(in.Name+"."+m.Name, singleLine(m.Definition)))
+		}
+		toc = append(toc, e)
+	}
+
+	p.TOC = toc
+}
+
+// singleLine joins the lines of a definition, like the fields of a struct parameter, into a single line.
+func singleLine(definition string) string {
+	return strings.Join(strings.Fields(definition), " ")
+}
+
+// typeSignature returns the first line of a type definition without the opening brace, like "type S struct".
+func typeSignature(definition string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(definition), "\n")
+	return strings.TrimSpace(strings.TrimSuffix(line, "{"))
+}